@@ -0,0 +1,57 @@
+// Command codecheck checks the duality and estimates the distances of linear codes, e.g.
+//
+//	go run ./cmd/codecheck -code Random -k 16 -l 1024 -seeds 8
+//
+// It exits with status 1 if a seed gives a degenerate code.
+package main
+
+import (
+	"RandomLinearCodePIR/dataobjects"
+	"RandomLinearCodePIR/linearcode"
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	defaults := linearcode.DefaultAnalysisOptions()
+	name := flag.String("code", linearcode.Random, "linear code name")
+	k := flag.Uint("k", 16, "dimension K of the query code")
+	l := flag.Uint("l", 1024, "dimension L of the database code")
+	p := flag.Uint("p", 65537, "prime field modulus")
+	seed := flag.Int64("seed", 1, "first seed of the code")
+	seeds := flag.Int("seeds", 1, "number of consecutive seeds to check")
+	exactLimit := flag.Uint64("exact-limit", defaults.ExactLimit, "column subsets enumerated for an exact distance")
+	samples := flag.Uint("samples", uint(defaults.Samples), "information sets sampled per estimate")
+	slack := flag.Uint("slack", uint(defaults.Slack), "distance below the Singleton bound tolerated before a seed is flagged")
+	flag.Parse()
+
+	options := defaults
+	options.ExactLimit = *exactLimit
+	options.Samples = uint32(*samples)
+	options.Slack = uint32(*slack)
+
+	degenerate := 0
+	for s := *seed; s < *seed+int64(*seeds); s++ {
+		analysis, err := linearcode.AnalyzeLinearCode(linearcode.LinearCodeConfig{
+			Name:  *name,
+			K:     uint32(*k),
+			L:     uint32(*l),
+			Field: dataobjects.NewPrimeField(uint32(*p)),
+			Seed:  s,
+		}, options)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		fmt.Println(analysis)
+		if analysis.Degenerate {
+			degenerate++
+		}
+	}
+
+	if degenerate > 0 {
+		fmt.Printf("%d of %d seeds are degenerate\n", degenerate, *seeds)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,259 @@
+package dataobjects
+
+import (
+	"math/bits"
+	"math/rand"
+)
+
+// BinaryField is the extension field GF(2^k) for 1 <= k <= 32. An element is the polynomial over
+// GF(2) whose coefficients are the bits of a uint32, and Poly is the irreducible polynomial of
+// degree k (including the x^k term) used for reduction. Addition is XOR and multiplication is a
+// carry-less product followed by Barrett reduction. Mod returns the number of elements 2^k, which
+// is 0 for k = 32, and GetChar returns 2.
+type BinaryField struct {
+	k    uint32
+	mask uint32
+	poly uint64
+	// mu = floor(x^(2k) / poly), the Barrett constant of poly
+	mu uint64
+}
+
+// NewBinaryField returns GF(2^k) with the numerically smallest irreducible polynomial of degree k,
+// e.g. x^8 + x^4 + x^3 + x + 1 for k = 8 which is the AES polynomial.
+func NewBinaryField(k uint32) *BinaryField {
+	if k == 0 || k > 32 {
+		panic("BinaryField only supports 1 <= k <= 32")
+	}
+
+	for low := uint64(1); low < uint64(1)<<k; low += 2 {
+		poly := uint64(1)<<k | low
+		if IsIrreducibleGF2(poly) {
+			return NewBinaryFieldWithPoly(k, poly)
+		}
+	}
+
+	panic("no irreducible polynomial found")
+}
+
+// NewBinaryFieldWithPoly returns GF(2^k) = GF(2)[x] / (poly), poly must be irreducible of degree k.
+func NewBinaryFieldWithPoly(k uint32, poly uint64) *BinaryField {
+	if k == 0 || k > 32 {
+		panic("BinaryField only supports 1 <= k <= 32")
+	}
+	if polyDegree(poly) != int(k) {
+		panic("the degree of the polynomial must be k")
+	}
+	if !IsIrreducibleGF2(poly) {
+		panic("the polynomial is not irreducible over GF(2)")
+	}
+
+	return &BinaryField{
+		k:    k,
+		mask: uint32((uint64(1) << k) - 1),
+		poly: poly,
+		mu:   barrettConstantGF2(k, poly),
+	}
+}
+
+func (f *BinaryField) Poly() uint64 { return f.poly }
+
+func (f *BinaryField) Add(a, b uint32) uint32 { return a ^ b }
+func (f *BinaryField) Sub(a, b uint32) uint32 { return a ^ b }
+func (f *BinaryField) Neg(a uint32) uint32    { return a }
+func (f *BinaryField) Mod() uint32            { return uint32(uint64(1) << f.k) }
+func (f *BinaryField) GetChar() uint32        { return 2 }
+
+func (f *BinaryField) Mul(a, b uint32) uint32 {
+	return f.reduce(clmulGF2(uint64(a), uint64(b)))
+}
+
+// Inv uses a^(2^k - 2) = a^-1, which takes k - 1 squarings and k - 2 multiplications.
+func (f *BinaryField) Inv(a uint32) uint32 {
+	if a == 0 {
+		panic("a is not invertible")
+	}
+
+	result := uint32(1)
+	base := a
+	for e := (uint64(1) << f.k) - 2; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			result = f.Mul(result, base)
+		}
+		base = f.Mul(base, base)
+	}
+
+	return result
+}
+
+func (f *BinaryField) AddVectors(r []uint32, ro uint64, a []uint32, ao uint64, b []uint32, bo uint64, length uint64) {
+	if USE_FAST_CODE {
+		BinaryFieldAddVectors(r, ro, a, ao, b, bo, length)
+	} else {
+		for i := uint64(0); i < length; i++ {
+			r[ro+i] = a[ao+i] ^ b[bo+i]
+		}
+	}
+}
+
+func (f *BinaryField) MulVector(r []uint32, ro uint64, a []uint32, ao uint64, b uint32, length uint64) {
+	if USE_FAST_CODE {
+		BinaryFieldMulVector(r, ro, a, ao, b, length, f.k, f.poly, f.mu)
+	} else {
+		for i := uint64(0); i < length; i++ {
+			r[ro+i] = f.Mul(a[ao+i], b)
+		}
+	}
+}
+
+func (f *BinaryField) SubVectors(r []uint32, ro uint64, a []uint32, ao uint64, b []uint32, bo uint64, length uint64) {
+	f.AddVectors(r, ro, a, ao, b, bo, length)
+}
+
+// Every element is its own additive inverse
+func (f *BinaryField) NegVector(r []uint32, ro uint64, length uint64) {}
+
+func (f *BinaryField) SampleElement() uint32 {
+	return rand.Uint32() & f.mask
+}
+
+func (f *BinaryField) SampleElementWithSeed(rng *rand.Rand) uint32 {
+	return rng.Uint32() & f.mask
+}
+
+func (f *BinaryField) SampleInvertibleVec(n uint32) []uint32 {
+	vec := AlignedMake[uint32](uint64(n))
+
+	for i := range vec {
+		for vec[i] == 0 {
+			vec[i] = rand.Uint32() & f.mask
+		}
+	}
+	return vec
+}
+
+func (f *BinaryField) SampleVector(n uint32) []uint32 {
+	vec := AlignedMake[uint32](uint64(n))
+
+	for i := range vec {
+		vec[i] = rand.Uint32() & f.mask
+	}
+	return vec
+}
+
+func (f *BinaryField) InvertVector(vec []uint32) []uint32 {
+	inv := AlignedMake[uint32](uint64(len(vec)))
+
+	for i := range vec {
+		inv[i] = f.Inv(vec[i])
+	}
+
+	return inv
+}
+
+// Barrett reduction of c with deg(c) < 2k, the quotient is exactly ((c >> k) * mu) >> k
+func (f *BinaryField) reduce(c uint64) uint32 {
+	q := clmulGF2(c>>f.k, f.mu) >> f.k
+	return uint32(c^clmulGF2(q, f.poly)) & f.mask
+}
+
+// IsIrreducibleGF2 runs Rabin's test on a polynomial of degree 1..32 over GF(2): f of degree k is
+// irreducible iff x^(2^k) = x mod f and gcd(x^(2^(k/q)) - x, f) = 1 for every prime q | k.
+func IsIrreducibleGF2(poly uint64) bool {
+	k := polyDegree(poly)
+	if k < 1 || k > 32 {
+		return false
+	}
+
+	// x^(2^i) mod poly by repeated squaring
+	frobenius := func(i int) uint64 {
+		r := polyModGF2(2, poly)
+		for t := 0; t < i; t++ {
+			r = polyMulModGF2(r, r, poly)
+		}
+		return r
+	}
+
+	if frobenius(k) != polyModGF2(2, poly) {
+		return false
+	}
+
+	for q := 2; q <= k; q++ {
+		if k%q != 0 || !isPrime(q) {
+			continue
+		}
+		if polyGcdGF2(frobenius(k/q)^2, poly) != 1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// clmulGF2 is the carry-less product, the caller ensures the result has degree < 64
+func clmulGF2(a, b uint64) uint64 {
+	r := uint64(0)
+	for ; b != 0; b >>= 1 {
+		if b&1 == 1 {
+			r ^= a
+		}
+		a <<= 1
+	}
+	return r
+}
+
+func polyDegree(a uint64) int {
+	return bits.Len64(a) - 1
+}
+
+// floor(x^(2k) / poly) without forming x^64 for k = 32: divide x^(2k-1) and finish the last step by hand
+func barrettConstantGF2(k uint32, poly uint64) uint64 {
+	top := uint64(1) << (2*k - 1)
+	q := polyDivGF2(top, poly)
+	r := polyModGF2(top, poly) << 1
+	if polyDegree(r) == int(k) {
+		return q<<1 | 1
+	}
+	return q << 1
+}
+
+func polyDivGF2(a, b uint64) uint64 {
+	q := uint64(0)
+	db := polyDegree(b)
+	for d := polyDegree(a); d >= db; d = polyDegree(a) {
+		q |= uint64(1) << (d - db)
+		a ^= b << (d - db)
+	}
+	return q
+}
+
+func polyModGF2(a, b uint64) uint64 {
+	db := polyDegree(b)
+	for d := polyDegree(a); d >= db; d = polyDegree(a) {
+		a ^= b << (d - db)
+	}
+	return a
+}
+
+// a, b are reduced modulo m with deg(m) <= 32, so the product fits into 64 bits
+func polyMulModGF2(a, b, m uint64) uint64 {
+	return polyModGF2(clmulGF2(a, b), m)
+}
+
+func polyGcdGF2(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, polyModGF2(a, b)
+	}
+	return a
+}
+
+func isPrime(n int) bool {
+	if n < 2 {
+		return false
+	}
+	for d := 2; d*d <= n; d++ {
+		if n%d == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -1,3 +1,5 @@
+//go:build cgo && !purego
+
 package dataobjects
 
 /*
@@ -54,3 +56,138 @@ func FieldNegVector(r []uint32, ro uint64, length uint64, p uint32) {
 	)
 	runtime.KeepAlive(r)
 }
+
+func RingAddVectors(r []uint32, ro uint64, a []uint32, ao uint64, b []uint32, bo uint64, length uint64, mask uint32) {
+	C.RingAddVectors(
+		(*C.uint32_t)(unsafe.Pointer(&r[0])), C.uint64_t(ro),
+		(*C.uint32_t)(unsafe.Pointer(&a[0])), C.uint64_t(ao),
+		(*C.uint32_t)(unsafe.Pointer(&b[0])), C.uint64_t(bo),
+		C.uint64_t(length), C.uint32_t(mask),
+	)
+	runtime.KeepAlive(r)
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(b)
+}
+
+func RingMulVector(r []uint32, ro uint64, a []uint32, ao uint64, b uint32, length uint64, mask uint32) {
+	C.RingMulVector(
+		(*C.uint32_t)(unsafe.Pointer(&r[0])), C.uint64_t(ro),
+		(*C.uint32_t)(unsafe.Pointer(&a[0])), C.uint64_t(ao),
+		C.uint32_t(b),
+		C.uint64_t(length), C.uint32_t(mask),
+	)
+	runtime.KeepAlive(r)
+	runtime.KeepAlive(a)
+}
+
+func RingSubVectors(r []uint32, ro uint64, a []uint32, ao uint64, b []uint32, bo uint64, length uint64, mask uint32) {
+	C.RingSubVectors(
+		(*C.uint32_t)(unsafe.Pointer(&r[0])), C.uint64_t(ro),
+		(*C.uint32_t)(unsafe.Pointer(&a[0])), C.uint64_t(ao),
+		(*C.uint32_t)(unsafe.Pointer(&b[0])), C.uint64_t(bo),
+		C.uint64_t(length), C.uint32_t(mask),
+	)
+	runtime.KeepAlive(r)
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(b)
+}
+
+func RingNegVector(r []uint32, ro uint64, length uint64, mask uint32) {
+	C.RingNegVector(
+		(*C.uint32_t)(unsafe.Pointer(&r[0])), C.uint64_t(ro),
+		C.uint64_t(length), C.uint32_t(mask),
+	)
+	runtime.KeepAlive(r)
+}
+
+func BinaryFieldAddVectors(r []uint32, ro uint64, a []uint32, ao uint64, b []uint32, bo uint64, length uint64) {
+	C.BinaryFieldAddVectors(
+		(*C.uint32_t)(unsafe.Pointer(&r[0])), C.uint64_t(ro),
+		(*C.uint32_t)(unsafe.Pointer(&a[0])), C.uint64_t(ao),
+		(*C.uint32_t)(unsafe.Pointer(&b[0])), C.uint64_t(bo),
+		C.uint64_t(length),
+	)
+	runtime.KeepAlive(r)
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(b)
+}
+
+func BinaryFieldMulVector(r []uint32, ro uint64, a []uint32, ao uint64, b uint32, length uint64, k uint32, poly, mu uint64) {
+	C.BinaryFieldMulVector(
+		(*C.uint32_t)(unsafe.Pointer(&r[0])), C.uint64_t(ro),
+		(*C.uint32_t)(unsafe.Pointer(&a[0])), C.uint64_t(ao),
+		C.uint32_t(b),
+		C.uint64_t(length), C.uint32_t(k), C.uint64_t(poly), C.uint64_t(mu),
+	)
+	runtime.KeepAlive(r)
+	runtime.KeepAlive(a)
+}
+
+func ExtensionFieldMulVector(r []uint32, ro uint64, a []uint32, ao uint64, b []uint32, length uint64, d uint32, modulus []uint32, p uint32) {
+	C.ExtensionFieldMulVector(
+		(*C.uint32_t)(unsafe.Pointer(&r[0])), C.uint64_t(ro),
+		(*C.uint32_t)(unsafe.Pointer(&a[0])), C.uint64_t(ao),
+		(*C.uint32_t)(unsafe.Pointer(&b[0])),
+		C.uint64_t(length), C.uint32_t(d),
+		(*C.uint32_t)(unsafe.Pointer(&modulus[0])), C.uint32_t(p),
+	)
+	runtime.KeepAlive(r)
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(b)
+	runtime.KeepAlive(modulus)
+}
+
+func Field64AddVectors(r []uint64, ro uint64, a []uint64, ao uint64, b []uint64, bo uint64, length uint64, p uint64) {
+	C.Field64AddVectors(
+		(*C.uint64_t)(unsafe.Pointer(&r[0])), C.uint64_t(ro),
+		(*C.uint64_t)(unsafe.Pointer(&a[0])), C.uint64_t(ao),
+		(*C.uint64_t)(unsafe.Pointer(&b[0])), C.uint64_t(bo),
+		C.uint64_t(length), C.uint64_t(p),
+	)
+	runtime.KeepAlive(r)
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(b)
+}
+
+func Field64MulVector(r []uint64, ro uint64, a []uint64, ao uint64, b uint64, length uint64, p uint64) {
+	C.Field64MulVector(
+		(*C.uint64_t)(unsafe.Pointer(&r[0])), C.uint64_t(ro),
+		(*C.uint64_t)(unsafe.Pointer(&a[0])), C.uint64_t(ao),
+		C.uint64_t(b),
+		C.uint64_t(length), C.uint64_t(p),
+	)
+	runtime.KeepAlive(r)
+	runtime.KeepAlive(a)
+}
+
+func Field64MulVectors(r []uint64, ro uint64, a []uint64, ao uint64, b []uint64, bo uint64, length uint64, p uint64) {
+	C.Field64MulVectors(
+		(*C.uint64_t)(unsafe.Pointer(&r[0])), C.uint64_t(ro),
+		(*C.uint64_t)(unsafe.Pointer(&a[0])), C.uint64_t(ao),
+		(*C.uint64_t)(unsafe.Pointer(&b[0])), C.uint64_t(bo),
+		C.uint64_t(length), C.uint64_t(p),
+	)
+	runtime.KeepAlive(r)
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(b)
+}
+
+func Field64SubVectors(r []uint64, ro uint64, a []uint64, ao uint64, b []uint64, bo uint64, length uint64, p uint64) {
+	C.Field64SubVectors(
+		(*C.uint64_t)(unsafe.Pointer(&r[0])), C.uint64_t(ro),
+		(*C.uint64_t)(unsafe.Pointer(&a[0])), C.uint64_t(ao),
+		(*C.uint64_t)(unsafe.Pointer(&b[0])), C.uint64_t(bo),
+		C.uint64_t(length), C.uint64_t(p),
+	)
+	runtime.KeepAlive(r)
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(b)
+}
+
+func Field64NegVector(r []uint64, ro uint64, length uint64, p uint64) {
+	C.Field64NegVector(
+		(*C.uint64_t)(unsafe.Pointer(&r[0])), C.uint64_t(ro),
+		C.uint64_t(length), C.uint64_t(p),
+	)
+	runtime.KeepAlive(r)
+}
@@ -0,0 +1,555 @@
+package dataobjects
+
+import (
+	"math/bits"
+	"math/rand"
+)
+
+// ExtensionField is GF(p^d) = F_p[x] / (f) for a monic irreducible f of degree d. An element is
+// stored as d limbs (a_0, ..., a_{d-1}) in F_p standing for a_0 + a_1 x + ... + a_{d-1} x^(d-1),
+// and a vector of n elements is the element-major array of n * d limbs. The limb API works for
+// every p and d with p^d < 2^64, so p = 65537 gives evaluation domains far larger than p.
+//
+// The Field interface needs an element to fit into one uint32. It is implemented on the packed
+// encoding a_0 + a_1 p + ... + a_{d-1} p^(d-1), which only exists when p^d <= 2^32 (see Packable).
+// The Field methods panic for larger fields, e.g. any d > 1 over p = 65537.
+type ExtensionField struct {
+	base *PrimeField
+	p    uint32
+	d    uint32
+	// low coefficients of f = x^d + modulus[d-1] x^(d-1) + ... + modulus[0]
+	modulus []uint32
+	// p^d, the number of elements
+	order uint64
+}
+
+// NewExtensionField returns GF(p^d) defined by the first irreducible binomial x^d - c, falling
+// back to the first irreducible trinomial x^d + x + c if no binomial exists.
+func NewExtensionField(p, d uint32) *ExtensionField {
+	if d == 0 {
+		panic("the extension degree must be positive")
+	}
+
+	modulus := make([]uint32, d)
+	if d == 1 {
+		return NewExtensionFieldWithModulus(p, modulus)
+	}
+
+	for c := uint32(1); c < p; c++ {
+		modulus[0] = p - c
+		if IsIrreducibleModP(append(append([]uint32{}, modulus...), 1), p) {
+			return NewExtensionFieldWithModulus(p, modulus)
+		}
+	}
+
+	modulus[1] = 1
+	for c := uint32(0); c < p; c++ {
+		modulus[0] = c
+		if IsIrreducibleModP(append(append([]uint32{}, modulus...), 1), p) {
+			return NewExtensionFieldWithModulus(p, modulus)
+		}
+	}
+
+	panic("no irreducible polynomial found")
+}
+
+// NewExtensionFieldWithModulus returns GF(p^d) for f = x^d + modulus[d-1] x^(d-1) + ... + modulus[0].
+func NewExtensionFieldWithModulus(p uint32, modulus []uint32) *ExtensionField {
+	d := uint32(len(modulus))
+	if d == 0 {
+		panic("the extension degree must be positive")
+	}
+
+	order := uint64(1)
+	for i := uint32(0); i < d; i++ {
+		hi, lo := bits.Mul64(order, uint64(p))
+		if hi != 0 {
+			panic("p^d must be smaller than 2^64")
+		}
+		order = lo
+	}
+
+	f := make([]uint32, d+1)
+	for i := range modulus {
+		f[i] = modulus[i] % p
+	}
+	f[d] = 1
+	if !IsIrreducibleModP(f, p) {
+		panic("the modulus is not irreducible over F_p")
+	}
+
+	return &ExtensionField{
+		base:    NewPrimeField(p),
+		p:       p,
+		d:       d,
+		modulus: f[:d],
+		order:   order,
+	}
+}
+
+func (ef *ExtensionField) Base() *PrimeField { return ef.base }
+func (ef *ExtensionField) Degree() uint32    { return ef.d }
+func (ef *ExtensionField) Order() uint64     { return ef.order }
+func (ef *ExtensionField) Modulus() []uint32 { return ef.modulus }
+
+// Packable reports whether every element fits into one uint32, i.e. p^d <= 2^32
+func (ef *ExtensionField) Packable() bool { return ef.order <= uint64(1)<<32 }
+
+// =========== Limb API, every element is a slice of d limbs ===========
+
+func (ef *ExtensionField) NewElement() []uint32 {
+	return make([]uint32, ef.d)
+}
+
+// Embed maps a in F_p to (a, 0, ..., 0)
+func (ef *ExtensionField) Embed(a uint32) []uint32 {
+	r := ef.NewElement()
+	r[0] = a % ef.p
+	return r
+}
+
+// ElementFromIndex returns the element whose limbs are the base p digits of i < p^d, giving
+// p^d distinct elements that are used as evaluation points.
+func (ef *ExtensionField) ElementFromIndex(i uint64) []uint32 {
+	r := ef.NewElement()
+	for j := range r {
+		r[j] = uint32(i % uint64(ef.p))
+		i /= uint64(ef.p)
+	}
+	return r
+}
+
+func (ef *ExtensionField) IsZeroElement(a []uint32) bool {
+	for i := uint32(0); i < ef.d; i++ {
+		if a[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (ef *ExtensionField) IsOneElement(a []uint32) bool {
+	if a[0] != 1 {
+		return false
+	}
+	for i := uint32(1); i < ef.d; i++ {
+		if a[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (ef *ExtensionField) AddElement(r, a, b []uint32) {
+	for i := uint32(0); i < ef.d; i++ {
+		r[i] = ef.base.Add(a[i], b[i])
+	}
+}
+
+func (ef *ExtensionField) SubElement(r, a, b []uint32) {
+	for i := uint32(0); i < ef.d; i++ {
+		r[i] = ef.base.Sub(a[i], b[i])
+	}
+}
+
+func (ef *ExtensionField) NegElement(r, a []uint32) {
+	for i := uint32(0); i < ef.d; i++ {
+		r[i] = ef.base.Neg(a[i])
+	}
+}
+
+// MulElement computes the schoolbook product of degree 2d - 2 and reduces it by x^d = -sum modulus[j] x^j.
+// r may alias a or b.
+func (ef *ExtensionField) MulElement(r, a, b []uint32) {
+	d := ef.d
+	t := make([]uint64, 2*d-1)
+	for i := uint32(0); i < d; i++ {
+		if a[i] == 0 {
+			continue
+		}
+		for j := uint32(0); j < d; j++ {
+			t[i+j] = (t[i+j] + uint64(a[i])*uint64(b[j])) % uint64(ef.p)
+		}
+	}
+
+	for i := 2*d - 2; i >= d; i-- {
+		c := t[i]
+		if c == 0 {
+			continue
+		}
+		for j := uint32(0); j < d; j++ {
+			t[i-d+j] = (t[i-d+j] + uint64(ef.p-ef.modulus[j])*c) % uint64(ef.p)
+		}
+	}
+
+	for i := uint32(0); i < d; i++ {
+		r[i] = uint32(t[i])
+	}
+}
+
+// ExpElement computes r = a^e by square-and-multiply, r may alias a
+func (ef *ExtensionField) ExpElement(r, a []uint32, e uint64) {
+	base := append([]uint32{}, a[:ef.d]...)
+	res := ef.Embed(1)
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			ef.MulElement(res, res, base)
+		}
+		ef.MulElement(base, base, base)
+	}
+	copy(r, res)
+}
+
+// InvElement runs the extended Euclidean algorithm on a and f over F_p, r may alias a
+func (ef *ExtensionField) InvElement(r, a []uint32) {
+	if ef.IsZeroElement(a) {
+		panic("a is not invertible")
+	}
+
+	f := append(append([]uint32{}, ef.modulus...), 1)
+	g := polyTrimModP(append([]uint32{}, a[:ef.d]...))
+
+	// Invariant: t0 * a = r0 and t1 * a = r1 modulo f
+	r0, r1 := f, g
+	t0, t1 := []uint32{}, []uint32{1}
+	for len(r1) > 0 {
+		q, rem := polyDivModP(r0, r1, ef.p)
+		r0, r1 = r1, rem
+		t0, t1 = t1, polySubModP(t0, polyMulModP(q, t1, ef.p), ef.p)
+	}
+
+	// r0 is a nonzero constant since f is irreducible
+	c := ef.base.Inv(r0[0])
+	for i := uint32(0); i < ef.d; i++ {
+		r[i] = 0
+		if int(i) < len(t0) {
+			r[i] = ef.base.Mul(t0[i], c)
+		}
+	}
+}
+
+// NthRootOfUnity returns a primitive n-th root of unity, n must divide p^d - 1
+func (ef *ExtensionField) NthRootOfUnity(n uint64) []uint32 {
+	if n == 0 || (ef.order-1)%n != 0 {
+		panic("n does not divide the order of the multiplicative group")
+	}
+
+	factors := primeFactors(n)
+	h := ef.NewElement()
+	t := ef.NewElement()
+	for i := uint64(2); i < ef.order; i++ {
+		ef.ExpElement(h, ef.ElementFromIndex(i), (ef.order-1)/n)
+
+		primitive := true
+		for _, q := range factors {
+			ef.ExpElement(t, h, n/q)
+			if ef.IsOneElement(t) {
+				primitive = false
+				break
+			}
+		}
+		if primitive {
+			return h
+		}
+	}
+
+	return ef.Embed(1)
+}
+
+// MaxPowerOfTwoRootOfUnity returns the largest power of two dividing p^d - 1, the largest radix-2 NTT size
+func (ef *ExtensionField) MaxPowerOfTwoRootOfUnity() uint64 {
+	return uint64(1) << bits.TrailingZeros64(ef.order-1)
+}
+
+// =========== Limb vectors, offsets and lengths count elements ===========
+
+func (ef *ExtensionField) AddLimbVectors(r []uint32, ro uint64, a []uint32, ao uint64, b []uint32, bo uint64, length uint64) {
+	d := uint64(ef.d)
+	ef.base.AddVectors(r, ro*d, a, ao*d, b, bo*d, length*d)
+}
+
+func (ef *ExtensionField) SubLimbVectors(r []uint32, ro uint64, a []uint32, ao uint64, b []uint32, bo uint64, length uint64) {
+	d := uint64(ef.d)
+	ef.base.SubVectors(r, ro*d, a, ao*d, b, bo*d, length*d)
+}
+
+func (ef *ExtensionField) NegLimbVector(r []uint32, ro uint64, length uint64) {
+	d := uint64(ef.d)
+	ef.base.NegVector(r, ro*d, length*d)
+}
+
+// MulLimbVector multiplies every element of a by the element b
+func (ef *ExtensionField) MulLimbVector(r []uint32, ro uint64, a []uint32, ao uint64, b []uint32, length uint64) {
+	if USE_FAST_CODE {
+		ExtensionFieldMulVector(r, ro, a, ao, b, length, ef.d, ef.modulus, ef.p)
+	} else {
+		d := uint64(ef.d)
+		for i := uint64(0); i < length; i++ {
+			ef.MulElement(r[(ro+i)*d:(ro+i+1)*d], a[(ao+i)*d:(ao+i+1)*d], b)
+		}
+	}
+}
+
+// MulLimbVectorByBase multiplies every element of a by the base field element b
+func (ef *ExtensionField) MulLimbVectorByBase(r []uint32, ro uint64, a []uint32, ao uint64, b uint32, length uint64) {
+	d := uint64(ef.d)
+	ef.base.MulVector(r, ro*d, a, ao*d, b, length*d)
+}
+
+// =========== Packed encoding and the Field interface ===========
+
+func (ef *ExtensionField) Pack(a []uint32) uint32 {
+	ef.mustBePackable()
+	x := uint64(0)
+	for i := int(ef.d) - 1; i >= 0; i-- {
+		x = x*uint64(ef.p) + uint64(a[i])
+	}
+	return uint32(x)
+}
+
+func (ef *ExtensionField) Unpack(x uint32, r []uint32) {
+	ef.mustBePackable()
+	v := uint64(x)
+	for i := uint32(0); i < ef.d; i++ {
+		r[i] = uint32(v % uint64(ef.p))
+		v /= uint64(ef.p)
+	}
+}
+
+func (ef *ExtensionField) mustBePackable() {
+	if !ef.Packable() {
+		panic("p^d exceeds 2^32, use the limb API of ExtensionField")
+	}
+}
+
+func (ef *ExtensionField) packedBinaryOp(a, b uint32, op func(r, a, b []uint32)) uint32 {
+	ea, eb := ef.NewElement(), ef.NewElement()
+	ef.Unpack(a, ea)
+	ef.Unpack(b, eb)
+	op(ea, ea, eb)
+	return ef.Pack(ea)
+}
+
+func (ef *ExtensionField) Add(a, b uint32) uint32 { return ef.packedBinaryOp(a, b, ef.AddElement) }
+func (ef *ExtensionField) Sub(a, b uint32) uint32 { return ef.packedBinaryOp(a, b, ef.SubElement) }
+func (ef *ExtensionField) Mul(a, b uint32) uint32 { return ef.packedBinaryOp(a, b, ef.MulElement) }
+
+func (ef *ExtensionField) Neg(a uint32) uint32 {
+	ea := ef.NewElement()
+	ef.Unpack(a, ea)
+	ef.NegElement(ea, ea)
+	return ef.Pack(ea)
+}
+
+func (ef *ExtensionField) Inv(a uint32) uint32 {
+	ea := ef.NewElement()
+	ef.Unpack(a, ea)
+	ef.InvElement(ea, ea)
+	return ef.Pack(ea)
+}
+
+// Mod returns the number of elements p^d, which is 0 when p^d = 2^32
+func (ef *ExtensionField) Mod() uint32 {
+	ef.mustBePackable()
+	return uint32(ef.order)
+}
+
+func (ef *ExtensionField) GetChar() uint32 {
+	return ef.p
+}
+
+func (ef *ExtensionField) AddVectors(r []uint32, ro uint64, a []uint32, ao uint64, b []uint32, bo uint64, length uint64) {
+	for i := uint64(0); i < length; i++ {
+		r[ro+i] = ef.Add(a[ao+i], b[bo+i])
+	}
+}
+
+func (ef *ExtensionField) MulVector(r []uint32, ro uint64, a []uint32, ao uint64, b uint32, length uint64) {
+	for i := uint64(0); i < length; i++ {
+		r[ro+i] = ef.Mul(a[ao+i], b)
+	}
+}
+
+func (ef *ExtensionField) SubVectors(r []uint32, ro uint64, a []uint32, ao uint64, b []uint32, bo uint64, length uint64) {
+	for i := uint64(0); i < length; i++ {
+		r[ro+i] = ef.Sub(a[ao+i], b[bo+i])
+	}
+}
+
+func (ef *ExtensionField) NegVector(r []uint32, ro uint64, length uint64) {
+	for i := uint64(0); i < length; i++ {
+		r[ro+i] = ef.Neg(r[ro+i])
+	}
+}
+
+func (ef *ExtensionField) SampleElement() uint32 {
+	ef.mustBePackable()
+	return uint32(rand.Int63n(int64(ef.order)))
+}
+
+func (ef *ExtensionField) SampleElementWithSeed(rng *rand.Rand) uint32 {
+	ef.mustBePackable()
+	return uint32(rng.Int63n(int64(ef.order)))
+}
+
+func (ef *ExtensionField) SampleInvertibleVec(n uint32) []uint32 {
+	ef.mustBePackable()
+	vec := AlignedMake[uint32](uint64(n))
+
+	for i := range vec {
+		vec[i] = uint32(rand.Int63n(int64(ef.order)-1) + 1)
+	}
+	return vec
+}
+
+func (ef *ExtensionField) SampleVector(n uint32) []uint32 {
+	ef.mustBePackable()
+	vec := AlignedMake[uint32](uint64(n))
+
+	for i := range vec {
+		vec[i] = uint32(rand.Int63n(int64(ef.order)))
+	}
+	return vec
+}
+
+func (ef *ExtensionField) InvertVector(vec []uint32) []uint32 {
+	inv := AlignedMake[uint32](uint64(len(vec)))
+
+	for i := range vec {
+		inv[i] = ef.Inv(vec[i])
+	}
+
+	return inv
+}
+
+// =========== Polynomials over F_p, coefficients from low to high and trimmed ===========
+
+// IsIrreducibleModP runs Rabin's test on f (low to high coefficients, deg f >= 1) over F_p:
+// f of degree d is irreducible iff x^(p^d) = x mod f and gcd(x^(p^(d/q)) - x, f) = 1 for every prime q | d.
+func IsIrreducibleModP(f []uint32, p uint32) bool {
+	f = polyTrimModP(append([]uint32{}, f...))
+	d := len(f) - 1
+	if d < 1 {
+		return false
+	}
+	if d == 1 {
+		return true
+	}
+
+	x := []uint32{0, 1}
+	// x^(p^i) mod f by applying the Frobenius map i times
+	frobenius := func(i int) []uint32 {
+		r := x
+		for t := 0; t < i; t++ {
+			r = polyPowModP(r, uint64(p), f, p)
+		}
+		return r
+	}
+
+	if len(polySubModP(frobenius(d), x, p)) != 0 {
+		return false
+	}
+
+	for _, q := range primeFactors(uint64(d)) {
+		g := polyGcdModP(polySubModP(frobenius(d/int(q)), x, p), f, p)
+		if len(g) != 1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func polyTrimModP(a []uint32) []uint32 {
+	for len(a) > 0 && a[len(a)-1] == 0 {
+		a = a[:len(a)-1]
+	}
+	return a
+}
+
+func polySubModP(a, b []uint32, p uint32) []uint32 {
+	r := make([]uint32, max(len(a), len(b)))
+	for i := range r {
+		var x, y uint64
+		if i < len(a) {
+			x = uint64(a[i])
+		}
+		if i < len(b) {
+			y = uint64(b[i])
+		}
+		r[i] = uint32((x + uint64(p) - y) % uint64(p))
+	}
+	return polyTrimModP(r)
+}
+
+func polyMulModP(a, b []uint32, p uint32) []uint32 {
+	if len(a) == 0 || len(b) == 0 {
+		return []uint32{}
+	}
+	r := make([]uint32, len(a)+len(b)-1)
+	for i := range a {
+		for j := range b {
+			r[i+j] = uint32((uint64(r[i+j]) + uint64(a[i])*uint64(b[j])) % uint64(p))
+		}
+	}
+	return polyTrimModP(r)
+}
+
+// polyDivModP returns the quotient and remainder of a / b for nonzero b
+func polyDivModP(a, b []uint32, p uint32) ([]uint32, []uint32) {
+	rem := polyTrimModP(append([]uint32{}, a...))
+	if len(rem) < len(b) {
+		return []uint32{}, rem
+	}
+
+	field := NewPrimeField(p)
+	lead := field.Inv(b[len(b)-1])
+	q := make([]uint32, len(rem)-len(b)+1)
+	for len(rem) >= len(b) {
+		shift := len(rem) - len(b)
+		c := field.Mul(rem[len(rem)-1], lead)
+		q[shift] = c
+		for j := range b {
+			rem[shift+j] = field.Sub(rem[shift+j], field.Mul(c, b[j]))
+		}
+		rem = polyTrimModP(rem)
+	}
+	return polyTrimModP(q), rem
+}
+
+func polyGcdModP(a, b []uint32, p uint32) []uint32 {
+	a, b = polyTrimModP(a), polyTrimModP(b)
+	for len(b) > 0 {
+		_, r := polyDivModP(a, b, p)
+		a, b = b, r
+	}
+	return a
+}
+
+func polyPowModP(a []uint32, e uint64, f []uint32, p uint32) []uint32 {
+	res := []uint32{1}
+	_, base := polyDivModP(a, f, p)
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			_, res = polyDivModP(polyMulModP(res, base, p), f, p)
+		}
+		_, base = polyDivModP(polyMulModP(base, base, p), f, p)
+	}
+	return res
+}
+
+func primeFactors(n uint64) []uint64 {
+	factors := []uint64{}
+	for q := uint64(2); q*q <= n; q++ {
+		if n%q == 0 {
+			factors = append(factors, q)
+			for n%q == 0 {
+				n /= q
+			}
+		}
+	}
+	if n > 1 {
+		factors = append(factors, n)
+	}
+	return factors
+}
@@ -0,0 +1,176 @@
+package dataobjects
+
+import (
+	"math/bits"
+	"math/rand"
+)
+
+// Field64 is the uint64 counterpart of Field for moduli that do not fit into 32 bits,
+// e.g. ~60-bit NTT-friendly primes. Vectors are []uint64 and products are formed in 128 bits.
+type Field64 interface {
+	Add(a, b uint64) uint64
+	Mul(a, b uint64) uint64
+	Sub(a, b uint64) uint64
+	Neg(a uint64) uint64
+	Inv(a uint64) uint64
+	Mod() uint64
+	AddVectors(r []uint64, ro uint64, a []uint64, ao uint64, b []uint64, bo uint64, length uint64)
+	MulVector(r []uint64, ro uint64, a []uint64, ao uint64, b uint64, length uint64)
+	SubVectors(r []uint64, ro uint64, a []uint64, ao uint64, b []uint64, bo uint64, length uint64)
+	NegVector(r []uint64, ro uint64, length uint64)
+	GetChar() uint64
+	SampleElementWithSeed(rng *rand.Rand) uint64
+	SampleElement() uint64
+	SampleInvertibleVec(n uint32) []uint64
+	InvertVector(vec []uint64) []uint64
+	SampleVector(n uint32) []uint64
+}
+
+type PrimeField64 struct {
+	p uint64
+}
+
+func NewPrimeField64(p uint64) *PrimeField64 {
+	return &PrimeField64{p: p}
+}
+
+func (f *PrimeField64) Add(a, b uint64) uint64 {
+	s, carry := bits.Add64(a, b, 0)
+	if carry != 0 || s >= f.p {
+		s -= f.p
+	}
+	return s
+}
+
+func (f *PrimeField64) Mul(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	_, rem := bits.Div64(hi%f.p, lo, f.p)
+	return rem
+}
+
+func (f *PrimeField64) Sub(a, b uint64) uint64 {
+	if a >= b {
+		return a - b
+	}
+	return f.p - (b - a)
+}
+
+func (f *PrimeField64) Neg(a uint64) uint64 {
+	if a == 0 {
+		return 0
+	}
+	return f.p - a
+}
+
+// a^(p-2) by Fermat's little theorem, since p may exceed the range of the signed extended Euclid
+func (f *PrimeField64) Inv(a uint64) uint64 {
+	if a%f.p == 0 {
+		panic("a is not invertible")
+	}
+
+	result := uint64(1)
+	base := a % f.p
+	for e := f.p - 2; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			result = f.Mul(result, base)
+		}
+		base = f.Mul(base, base)
+	}
+	return result
+}
+
+func (f *PrimeField64) Mod() uint64 {
+	return f.p
+}
+
+func (f *PrimeField64) GetChar() uint64 {
+	return f.p
+}
+
+func (f *PrimeField64) AddVectors(r []uint64, ro uint64, a []uint64, ao uint64, b []uint64, bo uint64, length uint64) {
+	if USE_FAST_CODE {
+		Field64AddVectors(r, ro, a, ao, b, bo, length, f.p)
+	} else {
+		for i := uint64(0); i < length; i++ {
+			r[ro+i] = f.Add(a[ao+i], b[bo+i])
+		}
+	}
+}
+
+func (f *PrimeField64) MulVector(r []uint64, ro uint64, a []uint64, ao uint64, b uint64, length uint64) {
+	if USE_FAST_CODE {
+		Field64MulVector(r, ro, a, ao, b, length, f.p)
+	} else {
+		for i := uint64(0); i < length; i++ {
+			r[ro+i] = f.Mul(a[ao+i], b)
+		}
+	}
+}
+
+func (f *PrimeField64) SubVectors(r []uint64, ro uint64, a []uint64, ao uint64, b []uint64, bo uint64, length uint64) {
+	if USE_FAST_CODE {
+		Field64SubVectors(r, ro, a, ao, b, bo, length, f.p)
+	} else {
+		for i := uint64(0); i < length; i++ {
+			r[ro+i] = f.Sub(a[ao+i], b[bo+i])
+		}
+	}
+}
+
+func (f *PrimeField64) NegVector(r []uint64, ro uint64, length uint64) {
+	if USE_FAST_CODE {
+		Field64NegVector(r, ro, length, f.p)
+	} else {
+		for i := uint64(0); i < length; i++ {
+			r[ro+i] = f.Neg(r[ro+i])
+		}
+	}
+}
+
+// Rejection sampling below the largest multiple of p to stay uniform
+func (f *PrimeField64) sample(next func() uint64) uint64 {
+	limit := ^uint64(0) - ^uint64(0)%f.p
+	for {
+		if x := next(); x < limit {
+			return x % f.p
+		}
+	}
+}
+
+func (f *PrimeField64) SampleElement() uint64 {
+	return f.sample(rand.Uint64)
+}
+
+func (f *PrimeField64) SampleElementWithSeed(rng *rand.Rand) uint64 {
+	return f.sample(rng.Uint64)
+}
+
+func (f *PrimeField64) SampleInvertibleVec(n uint32) []uint64 {
+	vec := AlignedMake[uint64](uint64(n))
+
+	for i := range vec {
+		for vec[i] == 0 {
+			vec[i] = f.sample(rand.Uint64)
+		}
+	}
+	return vec
+}
+
+func (f *PrimeField64) SampleVector(n uint32) []uint64 {
+	vec := AlignedMake[uint64](uint64(n))
+
+	for i := range vec {
+		vec[i] = f.sample(rand.Uint64)
+	}
+	return vec
+}
+
+func (f *PrimeField64) InvertVector(vec []uint64) []uint64 {
+	inv := AlignedMake[uint64](uint64(len(vec)))
+
+	for i := range vec {
+		inv[i] = f.Inv(vec[i])
+	}
+
+	return inv
+}
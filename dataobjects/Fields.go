@@ -23,6 +23,15 @@ type Field interface {
 	SampleVector(n uint32) []uint32
 }
 
+// FieldSize returns the number of elements of field, Mod returns 0 for the 2^32 elements of
+// RingZ2k and BinaryField with k = 32
+func FieldSize(field Field) uint64 {
+	if mod := field.Mod(); mod != 0 {
+		return uint64(mod)
+	}
+	return uint64(1) << 32
+}
+
 type PrimeField struct {
 	p uint32
 }
@@ -81,7 +90,7 @@ func (f *PrimeField) AddVectors(r []uint32, ro uint64, a []uint32, ao uint64, b
 		FieldAddVectors(r, ro, a, ao, b, bo, length, f.p)
 	} else {
 		for i := uint64(0); i < length; i++ {
-			r[ro+i] = uint32(uint64(a[ao+i]) + uint64(b[bo+i])%uint64(f.p))
+			r[ro+i] = uint32((uint64(a[ao+i]) + uint64(b[bo+i])) % uint64(f.p))
 		}
 	}
 }
@@ -152,19 +161,117 @@ func (f *PrimeField) InvertVector(vec []uint32) []uint32 {
 	return inv
 }
 
+// RingZ2k is the ring of integers modulo 2^k for 1 <= k <= 32. It is not a field, only the odd
+// elements are invertible, but it implements the Field interface so that it can be used by the
+// MVP schemes. All arithmetic is native uint32 arithmetic truncated by the mask, so for k = 32
+// no reduction happens at all and Mod/GetChar return 0 to denote 2^32.
 type RingZ2k struct {
 	k    uint32
 	mask uint32
 }
 
 func NewRingZ2k(k uint32) *RingZ2k {
-	return &RingZ2k{k: k, mask: (1 << k) - 1}
+	if k == 0 || k > 32 {
+		panic("RingZ2k only supports 1 <= k <= 32")
+	}
+	return &RingZ2k{k: k, mask: uint32((uint64(1) << k) - 1)}
+}
+
+func (r *RingZ2k) Add(a, b uint32) uint32 { return (a + b) & r.mask }
+func (r *RingZ2k) Sub(a, b uint32) uint32 { return (a - b) & r.mask }
+func (r *RingZ2k) Mul(a, b uint32) uint32 { return (a * b) & r.mask }
+func (r *RingZ2k) Neg(a uint32) uint32    { return (-a) & r.mask }
+func (r *RingZ2k) Mod() uint32            { return uint32(uint64(1) << r.k) }
+func (r *RingZ2k) GetChar() uint32        { return uint32(uint64(1) << r.k) }
+
+// Newton iteration x <- x * (2 - a * x) doubles the number of correct low bits each round.
+// Any odd a satisfies a * a = 1 mod 8, so x = a starts with 3 correct bits and 4 rounds reach 48 >= 32.
+func (r *RingZ2k) Inv(a uint32) uint32 {
+	if a&1 == 0 {
+		panic("a is not invertible")
+	}
+
+	x := a
+	for i := 0; i < 4; i++ {
+		x *= 2 - a*x
+	}
+
+	return x & r.mask
+}
+
+func (r *RingZ2k) AddVectors(res []uint32, ro uint64, a []uint32, ao uint64, b []uint32, bo uint64, length uint64) {
+	if USE_FAST_CODE {
+		RingAddVectors(res, ro, a, ao, b, bo, length, r.mask)
+	} else {
+		for i := uint64(0); i < length; i++ {
+			res[ro+i] = (a[ao+i] + b[bo+i]) & r.mask
+		}
+	}
+}
+
+func (r *RingZ2k) MulVector(res []uint32, ro uint64, a []uint32, ao uint64, b uint32, length uint64) {
+	if USE_FAST_CODE {
+		RingMulVector(res, ro, a, ao, b, length, r.mask)
+	} else {
+		for i := uint64(0); i < length; i++ {
+			res[ro+i] = (a[ao+i] * b) & r.mask
+		}
+	}
+}
+
+func (r *RingZ2k) SubVectors(res []uint32, ro uint64, a []uint32, ao uint64, b []uint32, bo uint64, length uint64) {
+	if USE_FAST_CODE {
+		RingSubVectors(res, ro, a, ao, b, bo, length, r.mask)
+	} else {
+		for i := uint64(0); i < length; i++ {
+			res[ro+i] = (a[ao+i] - b[bo+i]) & r.mask
+		}
+	}
 }
 
-func (r *RingZ2k) Add(a, b uint32) uint32      { return (a + b) & r.mask }
-func (r *RingZ2k) Sub(a, b uint32) uint32      { return (a - b) & r.mask }
-func (r *RingZ2k) Mul(a, b uint32) uint32      { return (a * b) & r.mask }
-func (r *RingZ2k) Neg(a uint32) uint32         { return (-a) & r.mask }
-func (r *RingZ2k) Inv(a uint32) uint32         { panic("not implemented") }
-func (r *RingZ2k) Mod() uint32                 { return 1 << r.k }
-func (r *RingZ2k) SampleInvertibleVec() uint32 { return 1 << r.k }
+func (r *RingZ2k) NegVector(res []uint32, ro uint64, length uint64) {
+	if USE_FAST_CODE {
+		RingNegVector(res, ro, length, r.mask)
+	} else {
+		for i := uint64(0); i < length; i++ {
+			res[ro+i] = (-res[ro+i]) & r.mask
+		}
+	}
+}
+
+func (r *RingZ2k) SampleElement() uint32 {
+	return rand.Uint32() & r.mask
+}
+
+func (r *RingZ2k) SampleElementWithSeed(rng *rand.Rand) uint32 {
+	return rng.Uint32() & r.mask
+}
+
+// The units of Z_2^k are exactly the odd elements
+func (r *RingZ2k) SampleInvertibleVec(n uint32) []uint32 {
+	vec := AlignedMake[uint32](uint64(n))
+
+	for i := range vec {
+		vec[i] = (rand.Uint32() | 1) & r.mask
+	}
+	return vec
+}
+
+func (r *RingZ2k) SampleVector(n uint32) []uint32 {
+	vec := AlignedMake[uint32](uint64(n))
+
+	for i := range vec {
+		vec[i] = rand.Uint32() & r.mask
+	}
+	return vec
+}
+
+func (r *RingZ2k) InvertVector(vec []uint32) []uint32 {
+	inv := AlignedMake[uint32](uint64(len(vec)))
+
+	for i := range vec {
+		inv[i] = r.Inv(vec[i])
+	}
+
+	return inv
+}
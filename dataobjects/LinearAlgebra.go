@@ -0,0 +1,194 @@
+package dataobjects
+
+// Dense linear algebra over any Field on row-major matrices, convert other layouts with ToRowMajor.
+// The row operations run on the field's vector kernels. Over RingZ2k only units are used as pivots,
+// so the results are exact when the matrix can be reduced that way, e.g. when it is invertible.
+
+func Transpose(m *Matrix) *Matrix {
+	t := NewMatrix(m.Cols, m.Rows)
+	for i := uint32(0); i < m.Rows; i++ {
+		for j := uint32(0); j < m.Cols; j++ {
+			t.Set(j, i, m.At(i, j))
+		}
+	}
+	return t
+}
+
+// MatMul returns a * b, row i of the product is accumulated as sum_k a[i][k] * b[k]
+func MatMul(field Field, a, b *Matrix) *Matrix {
+	a.mustBeRowMajor()
+	b.mustBeRowMajor()
+	if a.Cols != b.Rows {
+		panic("matrix dimensions do not match")
+	}
+
+	c := NewMatrix(a.Rows, b.Cols)
+	tmp := AlignedMake[uint32](uint64(b.Cols))
+	cols := uint64(b.Cols)
+	for i := uint32(0); i < a.Rows; i++ {
+		ci := uint64(i) * cols
+		for k := uint32(0); k < a.Cols; k++ {
+			aik := a.At(i, k)
+			if aik == 0 {
+				continue
+			}
+			field.MulVector(tmp, 0, b.Data, uint64(k)*cols, aik, cols)
+			field.AddVectors(c.Data, ci, c.Data, ci, tmp, 0, cols)
+		}
+	}
+	return c
+}
+
+// MatVec returns m * v
+func MatVec(field Field, m *Matrix, v []uint32) []uint32 {
+	if uint32(len(v)) < m.Cols {
+		panic("vector is shorter than the number of columns")
+	}
+
+	r := AlignedMake[uint32](uint64(m.Rows))
+	for i := uint32(0); i < m.Rows; i++ {
+		sum := uint32(0)
+		for j := uint32(0); j < m.Cols; j++ {
+			sum = field.Add(sum, field.Mul(m.At(i, j), v[j]))
+		}
+		r[i] = sum
+	}
+	return r
+}
+
+// RowReduce returns the reduced row echelon form of m and its pivot columns, m is left unchanged
+func RowReduce(field Field, m *Matrix) (*Matrix, []uint32) {
+	r := m.ToRowMajor()
+	return r, rowReduce(field, r, r.Cols)
+}
+
+// rowReduce brings the first cols columns of m into reduced row echelon form in place and returns the
+// pivot columns, the row operations are applied to the whole rows so that augmented columns are carried along
+func rowReduce(field Field, m *Matrix, cols uint32) []uint32 {
+	pivots := []uint32{}
+	width := uint64(m.Cols)
+	tmp := AlignedMake[uint32](width)
+	row := uint32(0)
+	for col := uint32(0); col < cols && row < m.Rows; col++ {
+		pivot := m.Rows
+		for i := row; i < m.Rows; i++ {
+			if isUnit(field, m.At(i, col)) {
+				pivot = i
+				break
+			}
+		}
+		if pivot == m.Rows {
+			continue
+		}
+
+		if pivot != row {
+			pr, rr := m.Row(pivot), m.Row(row)
+			for j := range pr {
+				pr[j], rr[j] = rr[j], pr[j]
+			}
+		}
+
+		// Entries left of col are zero in the pivot row, so only the tail of each row changes
+		start := uint64(row)*width + uint64(col)
+		length := width - uint64(col)
+		field.MulVector(m.Data, start, m.Data, start, field.Inv(m.At(row, col)), length)
+		for i := uint32(0); i < m.Rows; i++ {
+			c := m.At(i, col)
+			if i == row || c == 0 {
+				continue
+			}
+			field.MulVector(tmp, 0, m.Data, start, c, length)
+			ri := uint64(i)*width + uint64(col)
+			field.SubVectors(m.Data, ri, m.Data, ri, tmp, 0, length)
+		}
+		pivots = append(pivots, col)
+		row++
+	}
+	return pivots
+}
+
+func isUnit(field Field, a uint32) bool {
+	if _, ok := field.(*RingZ2k); ok {
+		return a&1 == 1
+	}
+	return a != 0
+}
+
+func Rank(field Field, m *Matrix) uint32 {
+	_, pivots := RowReduce(field, m)
+	return uint32(len(pivots))
+}
+
+// Inverse returns the inverse of the square matrix m, or false if m is singular
+func Inverse(field Field, m *Matrix) (*Matrix, bool) {
+	if m.Rows != m.Cols {
+		panic("only square matrices can be inverted")
+	}
+
+	n := m.Rows
+	aug := NewMatrix(n, 2*n)
+	for i := uint32(0); i < n; i++ {
+		copy(aug.Row(i)[:n], m.Row(i))
+		aug.Set(i, n+i, 1)
+	}
+	if uint32(len(rowReduce(field, aug, n))) < n {
+		return nil, false
+	}
+
+	inv := NewMatrix(n, n)
+	for i := uint32(0); i < n; i++ {
+		copy(inv.Row(i), aug.Row(i)[n:])
+	}
+	return inv, true
+}
+
+// NullSpace returns a matrix whose rows are a basis of {x : m * x = 0}
+func NullSpace(field Field, m *Matrix) *Matrix {
+	r, pivots := RowReduce(field, m)
+
+	isPivot := make([]bool, m.Cols)
+	for _, c := range pivots {
+		isPivot[c] = true
+	}
+
+	basis := NewMatrix(m.Cols-uint32(len(pivots)), m.Cols)
+	b := uint32(0)
+	for free := uint32(0); free < m.Cols; free++ {
+		if isPivot[free] {
+			continue
+		}
+		basis.Set(b, free, 1)
+		for i, c := range pivots {
+			basis.Set(b, c, field.Neg(r.At(uint32(i), free)))
+		}
+		b++
+	}
+	return basis
+}
+
+// Solve returns one x with m * x = b, setting all free variables to zero, or false if there is none
+func Solve(field Field, m *Matrix, b []uint32) ([]uint32, bool) {
+	if uint32(len(b)) < m.Rows {
+		panic("right-hand side is shorter than the number of rows")
+	}
+
+	aug := NewMatrix(m.Rows, m.Cols+1)
+	for i := uint32(0); i < m.Rows; i++ {
+		copy(aug.Row(i)[:m.Cols], m.Row(i))
+		aug.Set(i, m.Cols, b[i])
+	}
+	pivots := rowReduce(field, aug, m.Cols)
+
+	// The rows without a pivot are zero on the left, so b must be zero there too
+	for i := uint32(len(pivots)); i < m.Rows; i++ {
+		if aug.At(i, m.Cols) != 0 {
+			return nil, false
+		}
+	}
+
+	x := AlignedMake[uint32](uint64(m.Cols))
+	for i, c := range pivots {
+		x[c] = aug.At(uint32(i), m.Cols)
+	}
+	return x, true
+}
@@ -0,0 +1,105 @@
+package dataobjects
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"unsafe"
+)
+
+// MappedMatrix is a Matrix whose Data points into a read-only mapping of a file written by WriteMatrix,
+// so databases larger than RAM can be answered from the page cache. Data must not be written to, and must
+// not be used after Close. On platforms without mmap, and on big-endian hosts, the file is read into memory.
+type MappedMatrix struct {
+	Matrix
+	Header  *MatrixFileHeader
+	mapping []byte
+	mapped  bool
+}
+
+// MapMatrix maps a file written by WriteMatrix, refusing files of another scheme or with other params. The
+// checksum is not verified, as that reads the whole file, call Verify for that.
+func MapMatrix(path, scheme string, params []Param) (*MappedMatrix, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h, err := ReadMatrixHeader(bufio.NewReader(f))
+	if err != nil {
+		return nil, err
+	}
+	if err := h.Check(scheme, params); err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := uint64(h.HeaderLen) + h.PayloadLen
+	if uint64(info.Size()) != size {
+		return nil, fmt.Errorf("file has %d bytes, expected %d", info.Size(), size)
+	}
+
+	mm := &MappedMatrix{
+		Matrix: Matrix{
+			Rows:      h.Rows,
+			Cols:      h.Cols,
+			Layout:    h.Layout,
+			Blocks:    h.Blocks,
+			Modulus:   h.Modulus,
+			EntryBits: h.EntryBits,
+		},
+		Header: h,
+	}
+
+	if !hostIsLittleEndian() {
+		mm.mapping = AlignedMake[byte](size)
+		if _, err := f.ReadAt(mm.mapping, 0); err != nil {
+			return nil, err
+		}
+		mm.Data = AlignedMake[uint32](h.PayloadLen / 4)
+		for i := range mm.Data {
+			mm.Data[i] = binary.LittleEndian.Uint32(mm.mapping[uint64(h.HeaderLen)+4*uint64(i):])
+		}
+		return mm, nil
+	}
+
+	if mm.mapping, mm.mapped, err = mapFile(f, size); err != nil {
+		return nil, err
+	}
+	if h.PayloadLen > 0 {
+		mm.Data = unsafe.Slice((*uint32)(unsafe.Pointer(&mm.mapping[h.HeaderLen])), h.PayloadLen/4)
+	}
+	return mm, nil
+}
+
+// Verify checks the payload against the checksum in the header, reading it sequentially once
+func (mm *MappedMatrix) Verify() error {
+	if mm.mapping == nil {
+		return errors.New("matrix is closed")
+	}
+	if crc32.Checksum(mm.mapping[mm.Header.HeaderLen:], castagnoli) != mm.Header.Checksum {
+		return errors.New("payload checksum mismatch")
+	}
+	return nil
+}
+
+// Close unmaps the file, the matrix must not be used afterwards
+func (mm *MappedMatrix) Close() error {
+	mapping, mapped := mm.mapping, mm.mapped
+	mm.mapping, mm.Data = nil, nil
+	if mapping == nil || !mapped {
+		return nil
+	}
+	return unmapFile(mapping)
+}
+
+func hostIsLittleEndian() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}
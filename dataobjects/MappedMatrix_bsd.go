@@ -0,0 +1,6 @@
+//go:build darwin || freebsd || netbsd || openbsd
+
+package dataobjects
+
+// The syscall package has no madvise here, the kernel read-ahead still detects the sequential scan
+func adviseSequential(mapping []byte) {}
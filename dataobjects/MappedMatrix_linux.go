@@ -0,0 +1,8 @@
+package dataobjects
+
+import "syscall"
+
+func adviseSequential(mapping []byte) {
+	// Only a hint, the mapping works without it
+	_ = syscall.Madvise(mapping, syscall.MADV_SEQUENTIAL)
+}
@@ -0,0 +1,22 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package dataobjects
+
+import (
+	"os"
+	"syscall"
+)
+
+func mapFile(f *os.File, size uint64) ([]byte, bool, error) {
+	mapping, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, false, err
+	}
+	// Answer scans the payload front to back, so read ahead aggressively and drop pages behind the scan
+	adviseSequential(mapping)
+	return mapping, true, nil
+}
+
+func unmapFile(mapping []byte) error {
+	return syscall.Munmap(mapping)
+}
@@ -0,0 +1,18 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package dataobjects
+
+import "os"
+
+// Without mmap the file is read into memory, so the payload is still aligned
+func mapFile(f *os.File, size uint64) ([]byte, bool, error) {
+	mapping := AlignedMake[byte](size)
+	if _, err := f.ReadAt(mapping, 0); err != nil {
+		return nil, false, err
+	}
+	return mapping, false, nil
+}
+
+func unmapFile(mapping []byte) error {
+	return nil
+}
@@ -1,7 +1,329 @@
 package dataobjects
 
+import "fmt"
+
+// Layout describes how the entries of a Matrix are arranged in Data
+type Layout uint8
+
+const (
+	// Entry (i, j) is Data[i*Cols+j]
+	RowMajor Layout = iota
+	// The columns are split into Blocks blocks of Cols/Blocks columns, stored one block after the other and
+	// each block row-major, see mvp.TransformToBlockwise
+	BlockRowMajor
+	// The rows are split into Blocks slices of Rows/Blocks rows. The storage is the same as RowMajor, the
+	// slices are the ECC symbols of LpnMVP.
+	SlicedRowMajor
+	// Bit-packed F2 matrix stored by columns, row c of Data holds column c of the database with 32 entries
+	// per word. Rows is the number of database columns and Cols the number of words per column.
+	PackedColMajor
+)
+
+func (l Layout) String() string {
+	switch l {
+	case RowMajor:
+		return "row-major"
+	case BlockRowMajor:
+		return "block-row-major"
+	case SlicedRowMajor:
+		return "sliced-row-major"
+	case PackedColMajor:
+		return "packed-col-major"
+	}
+	return fmt.Sprintf("Layout(%d)", uint8(l))
+}
+
 type Matrix struct {
 	Rows uint32
 	Cols uint32
 	Data []uint32
+	// The zero value is RowMajor, so matrices built without metadata keep their meaning
+	Layout Layout
+	// Number of column blocks for BlockRowMajor, number of slices for SlicedRowMajor
+	Blocks uint32
+	// Modulus of the field the entries live in, 0 if unknown
+	Modulus uint32
+	// Significant bits of each word of Data, 0 if unknown
+	EntryBits uint32
+}
+
+// Matrix64 holds entries of a Field64, in the same layouts as Matrix
+type Matrix64 struct {
+	Rows uint32
+	Cols uint32
+	Data []uint64
+}
+
+// MatrixView is a window onto the storage of a Matrix, entry (i, j) is Data[i*Stride+j]
+type MatrixView struct {
+	Rows   uint32
+	Cols   uint32
+	Stride uint64
+	Data   []uint32
+}
+
+func (v MatrixView) At(i, j uint32) uint32 {
+	return v.Data[uint64(i)*v.Stride+uint64(j)]
+}
+
+func (v MatrixView) Set(i, j, x uint32) {
+	v.Data[uint64(i)*v.Stride+uint64(j)] = x
+}
+
+// Row returns row i of the view, sharing storage with the matrix
+func (v MatrixView) Row(i uint32) []uint32 {
+	start := uint64(i) * v.Stride
+	return v.Data[start : start+uint64(v.Cols)]
+}
+
+// Copy returns the entries of the view as a new row-major matrix
+func (v MatrixView) Copy() *Matrix {
+	m := NewMatrix(v.Rows, v.Cols)
+	for i := uint32(0); i < v.Rows; i++ {
+		copy(m.Row(i), v.Row(i))
+	}
+	return m
+}
+
+// NewMatrix returns the rows x cols zero matrix
+func NewMatrix(rows, cols uint32) *Matrix {
+	return &Matrix{Rows: rows, Cols: cols, Data: AlignedMake[uint32](uint64(rows) * uint64(cols))}
+}
+
+func IdentityMatrix(n uint32) *Matrix {
+	m := NewMatrix(n, n)
+	for i := uint32(0); i < n; i++ {
+		m.Set(i, i, 1)
+	}
+	return m
+}
+
+func (m *Matrix) At(i, j uint32) uint32 {
+	return m.Data[m.index(i, j)]
+}
+
+func (m *Matrix) Set(i, j, v uint32) {
+	m.Data[m.index(i, j)] = v
+}
+
+// Row returns row i of a row-major matrix, sharing storage with m
+func (m *Matrix) Row(i uint32) []uint32 {
+	m.mustBeRowMajor()
+	start := uint64(i) * uint64(m.Cols)
+	return m.Data[start : start+uint64(m.Cols)]
+}
+
+func (m *Matrix) Clone() *Matrix {
+	c := m.withLayout(m.Layout, m.Rows, m.Cols, m.Blocks)
+	copy(c.Data, m.Data[:len(c.Data)])
+	return c
+}
+
+// Equal compares shape, layout and entries, the modulus and entry bits are not compared
+func (m *Matrix) Equal(o *Matrix) bool {
+	if m.Rows != o.Rows || m.Cols != o.Cols || m.Layout != o.Layout || m.Blocks != o.Blocks {
+		return false
+	}
+	size := uint64(m.Rows) * uint64(m.Cols)
+	for i := uint64(0); i < size; i++ {
+		if m.Data[i] != o.Data[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Matrix) isRowMajor() bool {
+	return m.Layout == RowMajor || m.Layout == SlicedRowMajor
+}
+
+func (m *Matrix) mustBeRowMajor() {
+	if !m.isRowMajor() {
+		panic(fmt.Sprintf("matrix is %s, convert it with ToRowMajor first", m.Layout))
+	}
+}
+
+// blockWidth returns the number of columns per block of a BlockRowMajor matrix
+func (m *Matrix) blockWidth() uint32 {
+	if m.Blocks == 0 || m.Cols%m.Blocks != 0 {
+		panic(fmt.Sprintf("%d columns cannot be split into %d blocks", m.Cols, m.Blocks))
+	}
+	return m.Cols / m.Blocks
+}
+
+// index returns the position of entry (i, j) in Data
+func (m *Matrix) index(i, j uint32) uint64 {
+	switch m.Layout {
+	case RowMajor, SlicedRowMajor:
+		return uint64(i)*uint64(m.Cols) + uint64(j)
+	case BlockRowMajor:
+		b := m.blockWidth()
+		return (uint64(j/b)*uint64(m.Rows)+uint64(i))*uint64(b) + uint64(j%b)
+	}
+	panic(fmt.Sprintf("entries of a %s matrix cannot be addressed individually", m.Layout))
+}
+
+// CheckLayout returns an error unless m has the given layout, shape and number of blocks
+func (m *Matrix) CheckLayout(layout Layout, rows, cols, blocks uint32) error {
+	if m.Layout != layout {
+		return fmt.Errorf("matrix is %s, expected %s", m.Layout, layout)
+	}
+	if m.Rows != rows || m.Cols != cols {
+		return fmt.Errorf("matrix is %d x %d, expected %d x %d", m.Rows, m.Cols, rows, cols)
+	}
+	if (layout == BlockRowMajor || layout == SlicedRowMajor) && m.Blocks != blocks {
+		return fmt.Errorf("matrix has %d blocks, expected %d", m.Blocks, blocks)
+	}
+	if uint64(len(m.Data)) < uint64(rows)*uint64(cols) {
+		return fmt.Errorf("matrix holds %d entries, expected %d", len(m.Data), uint64(rows)*uint64(cols))
+	}
+	return nil
+}
+
+// AssertLayout panics if CheckLayout fails. Answer calls it on the encoded database, so that a matrix in
+// the wrong layout is not silently multiplied.
+func (m *Matrix) AssertLayout(layout Layout, rows, cols, blocks uint32) {
+	if err := m.CheckLayout(layout, rows, cols, blocks); err != nil {
+		panic(err.Error())
+	}
+}
+
+// Column returns column j as a Rows x 1 view
+func (m *Matrix) Column(j uint32) MatrixView {
+	return m.SubMatrix(0, j, m.Rows, 1)
+}
+
+// SubMatrix returns the rows x cols view starting at entry (row, col). For BlockRowMajor the view must
+// lie within one block.
+func (m *Matrix) SubMatrix(row, col, rows, cols uint32) MatrixView {
+	if row+rows > m.Rows || col+cols > m.Cols {
+		panic("sub-matrix exceeds the matrix")
+	}
+
+	stride := uint64(m.Cols)
+	if m.Layout == BlockRowMajor {
+		b := m.blockWidth()
+		if cols > 0 && col/b != (col+cols-1)/b {
+			panic("sub-matrix crosses a block boundary")
+		}
+		stride = uint64(b)
+	}
+	if rows == 0 || cols == 0 {
+		return MatrixView{Rows: rows, Cols: cols, Stride: stride}
+	}
+	start := m.index(row, col)
+	return MatrixView{Rows: rows, Cols: cols, Stride: stride, Data: m.Data[start : start+uint64(rows-1)*stride+uint64(cols)]}
+}
+
+// Block returns column block k of a BlockRowMajor matrix, or slice k of a SlicedRowMajor matrix
+func (m *Matrix) Block(k uint32) MatrixView {
+	if k >= m.Blocks {
+		panic(fmt.Sprintf("block %d out of %d", k, m.Blocks))
+	}
+
+	switch m.Layout {
+	case BlockRowMajor:
+		b := m.blockWidth()
+		return m.SubMatrix(0, k*b, m.Rows, b)
+	case SlicedRowMajor:
+		if m.Rows%m.Blocks != 0 {
+			panic(fmt.Sprintf("%d rows cannot be split into %d slices", m.Rows, m.Blocks))
+		}
+		r := m.Rows / m.Blocks
+		return m.SubMatrix(k*r, 0, r, m.Cols)
+	}
+	panic(fmt.Sprintf("a %s matrix has no blocks", m.Layout))
+}
+
+// withLayout returns a matrix with the metadata of m, the given layout and fresh storage
+func (m *Matrix) withLayout(layout Layout, rows, cols, blocks uint32) *Matrix {
+	r := NewMatrix(rows, cols)
+	r.Layout = layout
+	r.Blocks = blocks
+	r.Modulus = m.Modulus
+	r.EntryBits = m.EntryBits
+	return r
+}
+
+// ToRowMajor returns a row-major copy of m. A PackedColMajor matrix is unpacked to the 0/1 database
+// it holds, including the zero rows that pad it to a multiple of 32.
+func (m *Matrix) ToRowMajor() *Matrix {
+	switch m.Layout {
+	case RowMajor, SlicedRowMajor:
+		r := m.withLayout(RowMajor, m.Rows, m.Cols, 0)
+		copy(r.Data, m.Data)
+		return r
+	case BlockRowMajor:
+		r := m.withLayout(RowMajor, m.Rows, m.Cols, 0)
+		b := m.blockWidth()
+		for k := uint32(0); k < m.Blocks; k++ {
+			block := m.Block(k)
+			for i := uint32(0); i < m.Rows; i++ {
+				copy(r.Row(i)[k*b:(k+1)*b], block.Row(i))
+			}
+		}
+		return r
+	case PackedColMajor:
+		r := m.withLayout(RowMajor, m.Cols*32, m.Rows, 0)
+		r.EntryBits = 1
+		for c := uint32(0); c < m.Rows; c++ {
+			for w := uint32(0); w < m.Cols; w++ {
+				word := m.Data[uint64(c)*uint64(m.Cols)+uint64(w)]
+				for bit := uint32(0); bit < 32; bit++ {
+					r.Set(w*32+bit, c, (word>>bit)&1)
+				}
+			}
+		}
+		return r
+	}
+	panic(fmt.Sprintf("unknown layout %s", m.Layout))
+}
+
+// ToBlockRowMajor returns a copy of m with its columns split into the given number of blocks
+func (m *Matrix) ToBlockRowMajor(blocks uint32) *Matrix {
+	src := m
+	if !m.isRowMajor() {
+		src = m.ToRowMajor()
+	}
+
+	r := m.withLayout(BlockRowMajor, src.Rows, src.Cols, blocks)
+	b := r.blockWidth()
+	for k := uint32(0); k < blocks; k++ {
+		block := r.Block(k)
+		for i := uint32(0); i < src.Rows; i++ {
+			copy(block.Row(i), src.Row(i)[k*b:(k+1)*b])
+		}
+	}
+	return r
+}
+
+// ToSlicedRowMajor returns a copy of m with its rows split into the given number of slices
+func (m *Matrix) ToSlicedRowMajor(slices uint32) *Matrix {
+	r := m.ToRowMajor()
+	if slices == 0 || r.Rows%slices != 0 {
+		panic(fmt.Sprintf("%d rows cannot be split into %d slices", r.Rows, slices))
+	}
+	r.Layout = SlicedRowMajor
+	r.Blocks = slices
+	return r
+}
+
+// ToPackedColMajor packs a 0/1 matrix by columns, 32 rows per word, as used by pir.BasePIR
+func (m *Matrix) ToPackedColMajor() *Matrix {
+	src := m
+	if !m.isRowMajor() {
+		src = m.ToRowMajor()
+	}
+
+	words := (src.Rows + 31) / 32
+	r := m.withLayout(PackedColMajor, src.Cols, words, 0)
+	r.EntryBits = 32
+	for i := uint32(0); i < src.Rows; i++ {
+		for j := uint32(0); j < src.Cols; j++ {
+			if src.At(i, j) == 1 {
+				r.Data[uint64(j)*uint64(words)+uint64(i/32)] |= 1 << (i % 32)
+			}
+		}
+	}
+	return r
 }
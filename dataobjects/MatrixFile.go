@@ -0,0 +1,309 @@
+package dataobjects
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"strconv"
+)
+
+// File format of an encoded database, all integers little-endian:
+//
+//	magic      [8]byte  "RLCPIRDB"
+//	version    uint32
+//	headerLen  uint32   bytes before the payload, a multiple of ALIGNMENT
+//	scheme     uint16 length + bytes
+//	params     uint16 count, then per param a uint16 length + name and a uint16 length + value
+//	layout     uint32
+//	rows, cols, blocks, modulus, entryBits uint32
+//	payloadLen uint64   bytes, 4 * rows * cols
+//	checksum   uint32   CRC-32C of the payload
+//	padding up to headerLen
+//	payload    rows * cols uint32 entries in the stored layout
+//
+// The payload starts at a multiple of ALIGNMENT so that a mapped file can be used in place.
+const (
+	MatrixFileMagic   = "RLCPIRDB"
+	MatrixFileVersion = 1
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// Param is one scheme parameter recorded in the file header, values are compared as strings on load
+type Param struct {
+	Name  string
+	Value string
+}
+
+func UintParam(name string, v uint32) Param {
+	return Param{Name: name, Value: strconv.FormatUint(uint64(v), 10)}
+}
+
+type MatrixFileHeader struct {
+	Version    uint32
+	HeaderLen  uint32
+	Scheme     string
+	Params     []Param
+	Layout     Layout
+	Rows       uint32
+	Cols       uint32
+	Blocks     uint32
+	Modulus    uint32
+	EntryBits  uint32
+	PayloadLen uint64
+	Checksum   uint32
+}
+
+// Check returns an error unless the header belongs to the given scheme with exactly the given params
+func (h *MatrixFileHeader) Check(scheme string, params []Param) error {
+	if h.Scheme != scheme {
+		return fmt.Errorf("file holds a %q database, expected %q", h.Scheme, scheme)
+	}
+	if len(h.Params) != len(params) {
+		return fmt.Errorf("file has %d params, expected %d", len(h.Params), len(params))
+	}
+	for i, p := range params {
+		if h.Params[i] != p {
+			return fmt.Errorf("param %s = %s in file, expected %s = %s", h.Params[i].Name, h.Params[i].Value, p.Name, p.Value)
+		}
+	}
+	return nil
+}
+
+func (h *MatrixFileHeader) marshal() []byte {
+	var buf bytes.Buffer
+	putString := func(s string) {
+		binary.Write(&buf, binary.LittleEndian, uint16(len(s)))
+		buf.WriteString(s)
+	}
+
+	buf.WriteString(MatrixFileMagic)
+	binary.Write(&buf, binary.LittleEndian, h.Version)
+	binary.Write(&buf, binary.LittleEndian, uint32(0)) // headerLen, patched below
+	putString(h.Scheme)
+	binary.Write(&buf, binary.LittleEndian, uint16(len(h.Params)))
+	for _, p := range h.Params {
+		putString(p.Name)
+		putString(p.Value)
+	}
+	for _, v := range []uint32{uint32(h.Layout), h.Rows, h.Cols, h.Blocks, h.Modulus, h.EntryBits} {
+		binary.Write(&buf, binary.LittleEndian, v)
+	}
+	binary.Write(&buf, binary.LittleEndian, h.PayloadLen)
+	binary.Write(&buf, binary.LittleEndian, h.Checksum)
+
+	for uint64(buf.Len())%ALIGNMENT != 0 {
+		buf.WriteByte(0)
+	}
+	out := buf.Bytes()
+	h.HeaderLen = uint32(len(out))
+	binary.LittleEndian.PutUint32(out[12:], h.HeaderLen)
+	return out
+}
+
+// ReadMatrixHeader reads the header and leaves r at the start of the payload
+func ReadMatrixHeader(r io.Reader) (*MatrixFileHeader, error) {
+	fixed := make([]byte, 16)
+	if _, err := io.ReadFull(r, fixed); err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	if string(fixed[:8]) != MatrixFileMagic {
+		return nil, errors.New("not an encoded database file")
+	}
+
+	h := &MatrixFileHeader{
+		Version:   binary.LittleEndian.Uint32(fixed[8:]),
+		HeaderLen: binary.LittleEndian.Uint32(fixed[12:]),
+	}
+	if h.Version != MatrixFileVersion {
+		return nil, fmt.Errorf("unsupported file version %d", h.Version)
+	}
+	if h.HeaderLen < 16 || uint64(h.HeaderLen)%ALIGNMENT != 0 {
+		return nil, fmt.Errorf("invalid header length %d", h.HeaderLen)
+	}
+
+	rest := make([]byte, h.HeaderLen-16)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	buf := bytes.NewReader(rest)
+	getString := func() (string, error) {
+		var n uint16
+		if err := binary.Read(buf, binary.LittleEndian, &n); err != nil {
+			return "", err
+		}
+		s := make([]byte, n)
+		_, err := io.ReadFull(buf, s)
+		return string(s), err
+	}
+
+	var err error
+	if h.Scheme, err = getString(); err != nil {
+		return nil, fmt.Errorf("truncated header: %w", err)
+	}
+	var count uint16
+	if err := binary.Read(buf, binary.LittleEndian, &count); err != nil {
+		return nil, fmt.Errorf("truncated header: %w", err)
+	}
+	h.Params = make([]Param, count)
+	for i := range h.Params {
+		if h.Params[i].Name, err = getString(); err != nil {
+			return nil, fmt.Errorf("truncated header: %w", err)
+		}
+		if h.Params[i].Value, err = getString(); err != nil {
+			return nil, fmt.Errorf("truncated header: %w", err)
+		}
+	}
+	var fields [6]uint32
+	if err := binary.Read(buf, binary.LittleEndian, &fields); err != nil {
+		return nil, fmt.Errorf("truncated header: %w", err)
+	}
+	h.Layout, h.Rows, h.Cols, h.Blocks, h.Modulus, h.EntryBits = Layout(fields[0]), fields[1], fields[2], fields[3], fields[4], fields[5]
+	if err := binary.Read(buf, binary.LittleEndian, &h.PayloadLen); err != nil {
+		return nil, fmt.Errorf("truncated header: %w", err)
+	}
+	if err := binary.Read(buf, binary.LittleEndian, &h.Checksum); err != nil {
+		return nil, fmt.Errorf("truncated header: %w", err)
+	}
+
+	if h.Layout > PackedColMajor {
+		return nil, fmt.Errorf("unknown layout %d", h.Layout)
+	}
+	if h.PayloadLen != 4*uint64(h.Rows)*uint64(h.Cols) {
+		return nil, fmt.Errorf("payload of %d bytes does not match a %d x %d matrix", h.PayloadLen, h.Rows, h.Cols)
+	}
+	return h, nil
+}
+
+// WriteMatrix writes m with a header recording the scheme and its params
+func WriteMatrix(w io.Writer, scheme string, params []Param, m *Matrix) error {
+	size := uint64(m.Rows) * uint64(m.Cols)
+	if uint64(len(m.Data)) < size {
+		return fmt.Errorf("matrix holds %d entries, expected %d", len(m.Data), size)
+	}
+
+	h := &MatrixFileHeader{
+		Version:    MatrixFileVersion,
+		Scheme:     scheme,
+		Params:     params,
+		Layout:     m.Layout,
+		Rows:       m.Rows,
+		Cols:       m.Cols,
+		Blocks:     m.Blocks,
+		Modulus:    m.Modulus,
+		EntryBits:  m.EntryBits,
+		PayloadLen: 4 * size,
+		Checksum:   checksumEntries(m.Data[:size]),
+	}
+	if _, err := w.Write(h.marshal()); err != nil {
+		return err
+	}
+	return writeEntries(w, m.Data[:size])
+}
+
+// ReadMatrix reads a matrix written by WriteMatrix, refusing files of another scheme, with other params or
+// whose payload does not match the checksum
+func ReadMatrix(r io.Reader, scheme string, params []Param) (*Matrix, error) {
+	h, err := ReadMatrixHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.Check(scheme, params); err != nil {
+		return nil, err
+	}
+
+	m := &Matrix{
+		Rows:      h.Rows,
+		Cols:      h.Cols,
+		Data:      AlignedMake[uint32](h.PayloadLen / 4),
+		Layout:    h.Layout,
+		Blocks:    h.Blocks,
+		Modulus:   h.Modulus,
+		EntryBits: h.EntryBits,
+	}
+	if err := readEntries(r, m.Data); err != nil {
+		return nil, fmt.Errorf("reading payload: %w", err)
+	}
+	if checksumEntries(m.Data) != h.Checksum {
+		return nil, errors.New("payload checksum mismatch")
+	}
+	return m, nil
+}
+
+func SaveMatrix(path, scheme string, params []Param, m *Matrix) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriterSize(f, 1<<20)
+	if err := WriteMatrix(w, scheme, params, m); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func LoadMatrix(path, scheme string, params []Param) (*Matrix, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadMatrix(bufio.NewReaderSize(f, 1<<20), scheme, params)
+}
+
+// The payload is converted in chunks so that large databases need no second copy
+const entryChunk = 1 << 16
+
+func writeEntries(w io.Writer, data []uint32) error {
+	buf := make([]byte, 4*entryChunk)
+	for len(data) > 0 {
+		n := min(len(data), entryChunk)
+		for i := 0; i < n; i++ {
+			binary.LittleEndian.PutUint32(buf[4*i:], data[i])
+		}
+		if _, err := w.Write(buf[:4*n]); err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+func readEntries(r io.Reader, data []uint32) error {
+	buf := make([]byte, 4*entryChunk)
+	for len(data) > 0 {
+		n := min(len(data), entryChunk)
+		if _, err := io.ReadFull(r, buf[:4*n]); err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			data[i] = binary.LittleEndian.Uint32(buf[4*i:])
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+func checksumEntries(data []uint32) uint32 {
+	buf := make([]byte, 4*entryChunk)
+	crc := uint32(0)
+	for len(data) > 0 {
+		n := min(len(data), entryChunk)
+		for i := 0; i < n; i++ {
+			binary.LittleEndian.PutUint32(buf[4*i:], data[i])
+		}
+		crc = crc32.Update(crc, castagnoli, buf[:4*n])
+		data = data[n:]
+	}
+	return crc
+}
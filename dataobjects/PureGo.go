@@ -0,0 +1,155 @@
+//go:build purego || !cgo
+
+package dataobjects
+
+import "math/bits"
+
+// Go implementations of the kernels in fields.cpp, used when building with the purego tag or
+// CGO_ENABLED=0. They follow the scalar (NoSimd) C code so that both builds give identical results.
+
+func FieldAddVectors(r []uint32, ro uint64, a []uint32, ao uint64, b []uint32, bo uint64, length uint64, p uint32) {
+	for i := uint64(0); i < length; i++ {
+		r[ro+i] = uint32((uint64(a[ao+i]) + uint64(b[bo+i])) % uint64(p))
+	}
+}
+
+func FieldMulVector(r []uint32, ro uint64, a []uint32, ao uint64, b uint32, length uint64, p uint32) {
+	for i := uint64(0); i < length; i++ {
+		r[ro+i] = uint32((uint64(a[ao+i]) * uint64(b)) % uint64(p))
+	}
+}
+
+func FieldSubVectors(r []uint32, ro uint64, a []uint32, ao uint64, b []uint32, bo uint64, length uint64, p uint32) {
+	for i := uint64(0); i < length; i++ {
+		r[ro+i] = uint32((uint64(a[ao+i]) + uint64(p) - uint64(b[bo+i])) % uint64(p))
+	}
+}
+
+func FieldNegVector(r []uint32, ro uint64, length uint64, p uint32) {
+	for i := uint64(0); i < length; i++ {
+		r[ro+i] = uint32((uint64(p) - uint64(r[ro+i])) % uint64(p))
+	}
+}
+
+func RingAddVectors(r []uint32, ro uint64, a []uint32, ao uint64, b []uint32, bo uint64, length uint64, mask uint32) {
+	for i := uint64(0); i < length; i++ {
+		r[ro+i] = (a[ao+i] + b[bo+i]) & mask
+	}
+}
+
+func RingMulVector(r []uint32, ro uint64, a []uint32, ao uint64, b uint32, length uint64, mask uint32) {
+	for i := uint64(0); i < length; i++ {
+		r[ro+i] = (a[ao+i] * b) & mask
+	}
+}
+
+func RingSubVectors(r []uint32, ro uint64, a []uint32, ao uint64, b []uint32, bo uint64, length uint64, mask uint32) {
+	for i := uint64(0); i < length; i++ {
+		r[ro+i] = (a[ao+i] - b[bo+i]) & mask
+	}
+}
+
+func RingNegVector(r []uint32, ro uint64, length uint64, mask uint32) {
+	for i := uint64(0); i < length; i++ {
+		r[ro+i] = (0 - r[ro+i]) & mask
+	}
+}
+
+func BinaryFieldAddVectors(r []uint32, ro uint64, a []uint32, ao uint64, b []uint32, bo uint64, length uint64) {
+	for i := uint64(0); i < length; i++ {
+		r[ro+i] = a[ao+i] ^ b[bo+i]
+	}
+}
+
+func BinaryFieldMulVector(r []uint32, ro uint64, a []uint32, ao uint64, b uint32, length uint64, k uint32, poly, mu uint64) {
+	mask := uint64(1)<<k - 1
+	for i := uint64(0); i < length; i++ {
+		c := clmulGF2(uint64(a[ao+i]), uint64(b))
+		q := clmulGF2(c>>k, mu) >> k
+		r[ro+i] = uint32((c ^ clmulGF2(q, poly)) & mask)
+	}
+}
+
+// Offsets are in elements of d limbs, b is a single element
+func ExtensionFieldMulVector(r []uint32, ro uint64, a []uint32, ao uint64, b []uint32, length uint64, d uint32, modulus []uint32, p uint32) {
+	P := uint64(p)
+	D := uint64(d)
+
+	// negated modulus so that x^d = sum negf[j] x^j
+	negf := make([]uint64, d)
+	for j := range negf {
+		negf[j] = (P - uint64(modulus[j])) % P
+	}
+	t := make([]uint64, 2*d-1)
+
+	for e := uint64(0); e < length; e++ {
+		ae := a[(ao+e)*D : (ao+e+1)*D]
+		clear(t)
+
+		for i := uint32(0); i < d; i++ {
+			if ae[i] == 0 {
+				continue
+			}
+			for j := uint32(0); j < d; j++ {
+				t[i+j] = (t[i+j] + uint64(ae[i])*uint64(b[j])) % P
+			}
+		}
+
+		for i := 2*d - 2; i >= d; i-- {
+			c := t[i]
+			if c == 0 {
+				continue
+			}
+			for j := uint32(0); j < d; j++ {
+				t[i-d+j] = (t[i-d+j] + negf[j]*c) % P
+			}
+		}
+
+		re := r[(ro+e)*D : (ro+e+1)*D]
+		for i := range re {
+			re[i] = uint32(t[i])
+		}
+	}
+}
+
+func Field64AddVectors(r []uint64, ro uint64, a []uint64, ao uint64, b []uint64, bo uint64, length uint64, p uint64) {
+	for i := uint64(0); i < length; i++ {
+		s, carry := bits.Add64(a[ao+i], b[bo+i], 0)
+		if carry != 0 || s >= p {
+			s -= p
+		}
+		r[ro+i] = s
+	}
+}
+
+func Field64MulVector(r []uint64, ro uint64, a []uint64, ao uint64, b uint64, length uint64, p uint64) {
+	for i := uint64(0); i < length; i++ {
+		hi, lo := bits.Mul64(a[ao+i], b)
+		_, r[ro+i] = bits.Div64(hi%p, lo, p)
+	}
+}
+
+func Field64MulVectors(r []uint64, ro uint64, a []uint64, ao uint64, b []uint64, bo uint64, length uint64, p uint64) {
+	for i := uint64(0); i < length; i++ {
+		hi, lo := bits.Mul64(a[ao+i], b[bo+i])
+		_, r[ro+i] = bits.Div64(hi%p, lo, p)
+	}
+}
+
+func Field64SubVectors(r []uint64, ro uint64, a []uint64, ao uint64, b []uint64, bo uint64, length uint64, p uint64) {
+	for i := uint64(0); i < length; i++ {
+		if a[ao+i] >= b[bo+i] {
+			r[ro+i] = a[ao+i] - b[bo+i]
+		} else {
+			r[ro+i] = a[ao+i] + (p - b[bo+i])
+		}
+	}
+}
+
+func Field64NegVector(r []uint64, ro uint64, length uint64, p uint64) {
+	for i := uint64(0); i < length; i++ {
+		if r[ro+i] != 0 {
+			r[ro+i] = p - r[ro+i]
+		}
+	}
+}
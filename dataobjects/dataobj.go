@@ -5,7 +5,40 @@ import (
 	"unsafe"
 )
 
-const USE_FAST_CODE bool = true
+// USE_FAST_CODE selects the native (or purego) vector kernels over the plain Go reference loops.
+// The seeded generators draw from different streams in the two modes, so keys, databases and
+// queries must all be produced under the same setting. Use SetBackend between runs, not during one.
+var USE_FAST_CODE bool = true
+
+type Backend int
+
+const (
+	FastBackend Backend = iota
+	ReferenceBackend
+)
+
+func (b Backend) String() string {
+	if b == ReferenceBackend {
+		return "reference"
+	}
+	return "fast"
+}
+
+// SetBackend switches the kernels used by dataobjects and the packages built on it, and returns the
+// previous backend so callers can restore it. It is not safe to call concurrently with other work.
+func SetBackend(b Backend) Backend {
+	prev := GetBackend()
+	USE_FAST_CODE = b == FastBackend
+	return prev
+}
+
+func GetBackend() Backend {
+	if USE_FAST_CODE {
+		return FastBackend
+	}
+	return ReferenceBackend
+}
+
 const ALIGNMENT uint64 = 64 // aligns up to AVX512
 
 // AlignedMake creates a slice of type T with a specified length and default alignment.
@@ -0,0 +1,398 @@
+package dataobjects
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+// Test that RingZ2k inverses and fast vector kernels agree with the scalar ring operations
+func TestRingZ2k(t *testing.T) {
+	for _, k := range []uint32{1, 8, 16, 31, 32} {
+		ring := NewRingZ2k(k)
+		n := uint32(37)
+
+		units := ring.SampleInvertibleVec(n)
+		invs := ring.InvertVector(units)
+		for i := range units {
+			if ring.Mul(units[i], invs[i]) != 1 {
+				t.Fatalf("k = %d: %d * %d != 1", k, units[i], invs[i])
+			}
+		}
+
+		a := ring.SampleVector(n)
+		b := ring.SampleVector(n)
+		c := ring.SampleElement()
+		sum := AlignedMake[uint32](uint64(n))
+		diff := AlignedMake[uint32](uint64(n))
+		prod := AlignedMake[uint32](uint64(n))
+		neg := AlignedMake[uint32](uint64(n))
+		copy(neg, a)
+
+		ring.AddVectors(sum, 0, a, 0, b, 0, uint64(n))
+		ring.SubVectors(diff, 0, a, 0, b, 0, uint64(n))
+		ring.MulVector(prod, 0, a, 0, c, uint64(n))
+		ring.NegVector(neg, 0, uint64(n))
+
+		for i := range a {
+			if sum[i] != ring.Add(a[i], b[i]) || diff[i] != ring.Sub(a[i], b[i]) ||
+				prod[i] != ring.Mul(a[i], c) || neg[i] != ring.Neg(a[i]) {
+				t.Fatalf("k = %d: vector kernel mismatch at index %d", k, i)
+			}
+		}
+	}
+}
+
+// Test GF(2^k) against the AES field and check that the fast kernels match the scalar operations
+func TestBinaryField(t *testing.T) {
+	aes := NewBinaryField(8)
+	if aes.Poly() != 0x11B {
+		t.Fatalf("default polynomial for k = 8 is %#x, expected 0x11b", aes.Poly())
+	}
+	if aes.Mul(0x57, 0x83) != 0xC1 {
+		t.Fatalf("0x57 * 0x83 = %#x, expected 0xc1", aes.Mul(0x57, 0x83))
+	}
+
+	if IsIrreducibleGF2(0x11A) || IsIrreducibleGF2(0x105) {
+		t.Fatalf("reducible polynomial accepted")
+	}
+
+	for _, field := range []*BinaryField{NewBinaryField(1), aes, NewBinaryField(16),
+		NewBinaryFieldWithPoly(32, 0x10000008D), NewBinaryField(31)} {
+		n := uint32(29)
+
+		units := field.SampleInvertibleVec(n)
+		invs := field.InvertVector(units)
+		for i := range units {
+			if field.Mul(units[i], invs[i]) != 1 {
+				t.Fatalf("poly %#x: %d * %d != 1", field.Poly(), units[i], invs[i])
+			}
+		}
+
+		a := field.SampleVector(n)
+		b := field.SampleVector(n)
+		c := field.SampleElement()
+		sum := AlignedMake[uint32](uint64(n))
+		prod := AlignedMake[uint32](uint64(n))
+
+		field.AddVectors(sum, 0, a, 0, b, 0, uint64(n))
+		field.MulVector(prod, 0, a, 0, c, uint64(n))
+
+		for i := range a {
+			if sum[i] != field.Add(a[i], b[i]) || prod[i] != field.Mul(a[i], c) {
+				t.Fatalf("poly %#x: vector kernel mismatch at index %d", field.Poly(), i)
+			}
+			// distributivity against the schoolbook product
+			if field.Mul(sum[i], c) != field.Mul(a[i], c)^field.Mul(b[i], c) {
+				t.Fatalf("poly %#x: multiplication is not distributive", field.Poly())
+			}
+		}
+	}
+}
+
+// Test GF(p^d) limb arithmetic for p = 65537 and the packed Field encoding for a small field
+func TestExtensionField(t *testing.T) {
+	ef := NewExtensionField(65537, 2)
+	if ef.Packable() {
+		t.Fatalf("GF(65537^2) cannot be packed into uint32")
+	}
+
+	n := uint64(16)
+	a := make([]uint32, n*2)
+	b := ef.ElementFromIndex(123456789)
+	for i := range a {
+		a[i] = uint32(i*7919) % 65537
+	}
+
+	prod := make([]uint32, n*2)
+	ef.MulLimbVector(prod, 0, a, 0, b, n)
+
+	inv := ef.NewElement()
+	ef.InvElement(inv, b)
+	back := make([]uint32, n*2)
+	ef.MulLimbVector(back, 0, prod, 0, inv, n)
+
+	expected := ef.NewElement()
+	for i := uint64(0); i < n; i++ {
+		ef.MulElement(expected, a[i*2:(i+1)*2], b)
+		if expected[0] != prod[2*i] || expected[1] != prod[2*i+1] {
+			t.Fatalf("vector kernel mismatch at element %d", i)
+		}
+		if back[2*i] != a[2*i] || back[2*i+1] != a[2*i+1] {
+			t.Fatalf("a * b * b^-1 != a at element %d", i)
+		}
+	}
+
+	// 2^17 divides 65537^2 - 1 but not 65537 - 1
+	if ef.MaxPowerOfTwoRootOfUnity() != 1<<17 {
+		t.Fatalf("unexpected 2-adic order %d", ef.MaxPowerOfTwoRootOfUnity())
+	}
+	root := ef.NthRootOfUnity(1 << 17)
+	power := ef.NewElement()
+	ef.ExpElement(power, root, 1<<16)
+	if ef.IsOneElement(power) {
+		t.Fatalf("root of unity is not primitive")
+	}
+	ef.ExpElement(power, root, 1<<17)
+	if !ef.IsOneElement(power) {
+		t.Fatalf("root^n != 1")
+	}
+
+	small := NewExtensionField(257, 3)
+	x := small.SampleInvertibleVec(32)
+	y := small.SampleVector(32)
+	for i := range x {
+		if small.Mul(x[i], small.Inv(x[i])) != 1 {
+			t.Fatalf("packed inverse is wrong for %d", x[i])
+		}
+		if small.Sub(small.Add(x[i], y[i]), y[i]) != x[i] {
+			t.Fatalf("packed addition is wrong for %d, %d", x[i], y[i])
+		}
+	}
+}
+
+func TestPrimeField64(t *testing.T) {
+	// 2^60 - 2^18 + 1, an NTT-friendly prime
+	p := uint64(1152921504606584833)
+	field := NewPrimeField64(p)
+	P := new(big.Int).SetUint64(p)
+
+	n := uint32(1000)
+	a := field.SampleVector(n)
+	b := field.SampleInvertibleVec(n)
+	c := field.SampleElement()
+
+	sum := AlignedMake[uint64](uint64(n))
+	diff := AlignedMake[uint64](uint64(n))
+	prod := AlignedMake[uint64](uint64(n))
+	field.AddVectors(sum, 0, a, 0, b, 0, uint64(n))
+	field.SubVectors(diff, 0, a, 0, b, 0, uint64(n))
+	field.MulVector(prod, 0, a, 0, c, uint64(n))
+
+	inv := field.InvertVector(b)
+	for i := uint32(0); i < n; i++ {
+		A := new(big.Int).SetUint64(a[i])
+		B := new(big.Int).SetUint64(b[i])
+		if new(big.Int).Mod(new(big.Int).Add(A, B), P).Uint64() != sum[i] {
+			t.Fatalf("addition is wrong at %d", i)
+		}
+		if new(big.Int).Mod(new(big.Int).Sub(A, B), P).Uint64() != diff[i] {
+			t.Fatalf("subtraction is wrong at %d", i)
+		}
+		C := new(big.Int).SetUint64(c)
+		if new(big.Int).Mod(new(big.Int).Mul(A, C), P).Uint64() != prod[i] {
+			t.Fatalf("multiplication is wrong at %d", i)
+		}
+		if field.Mul(b[i], inv[i]) != 1 {
+			t.Fatalf("inverse is wrong for %d", b[i])
+		}
+	}
+
+	field.NegVector(sum, 0, uint64(n))
+	for i := uint32(0); i < n; i++ {
+		if field.Add(sum[i], field.Add(a[i], b[i])) != 0 {
+			t.Fatalf("negation is wrong at %d", i)
+		}
+	}
+}
+
+// Test inverse, nullspace and solve over prime, binary and extension fields, and inversion over Z/2^k
+func TestLinearAlgebra(t *testing.T) {
+	random := func(field Field, rows, cols uint32) *Matrix {
+		m := NewMatrix(rows, cols)
+		copy(m.Data, field.SampleVector(rows*cols))
+		return m
+	}
+	// Unit lower times unit upper triangular is invertible, also over Z/2^k
+	invertible := func(field Field, n uint32) *Matrix {
+		l, u := random(field, n, n), random(field, n, n)
+		for i := uint32(0); i < n; i++ {
+			for j := i; j < n; j++ {
+				l.Set(i, j, 0)
+				u.Set(j, i, 0)
+			}
+			l.Set(i, i, 1)
+			u.Set(i, i, 1)
+		}
+		return MatMul(field, l, u)
+	}
+
+	for _, field := range []Field{NewPrimeField(65537), NewBinaryField(8), NewExtensionField(257, 2)} {
+		a := invertible(field, 24)
+		inv, ok := Inverse(field, a)
+		if !ok {
+			t.Fatalf("mod %d: invertible matrix reported singular", field.Mod())
+		}
+		if !MatMul(field, a, inv).Equal(IdentityMatrix(24)) || !MatMul(field, inv, a).Equal(IdentityMatrix(24)) {
+			t.Fatalf("mod %d: a * a^-1 != I", field.Mod())
+		}
+
+		// 10 x 30 of rank 8: the last two rows are combinations of the others
+		b := random(field, 10, 30)
+		for j := uint32(0); j < b.Cols; j++ {
+			b.Set(8, j, field.Add(b.At(0, j), b.At(1, j)))
+			b.Set(9, j, field.Mul(b.At(2, j), 3))
+		}
+		if Rank(field, b) != 8 || Rank(field, Transpose(b)) != 8 {
+			t.Fatalf("mod %d: rank %d, expected 8", field.Mod(), Rank(field, b))
+		}
+
+		ns := NullSpace(field, b)
+		if ns.Rows != 22 || Rank(field, ns) != 22 {
+			t.Fatalf("mod %d: nullspace has %d rows, expected 22", field.Mod(), ns.Rows)
+		}
+		zero := MatMul(field, b, Transpose(ns))
+		for _, v := range zero.Data {
+			if v != 0 {
+				t.Fatalf("mod %d: nullspace vector not in the kernel", field.Mod())
+			}
+		}
+
+		x0 := field.SampleVector(30)
+		x, ok := Solve(field, b, MatVec(field, b, x0))
+		if !ok {
+			t.Fatalf("mod %d: consistent system not solved", field.Mod())
+		}
+		y, y0 := MatVec(field, b, x), MatVec(field, b, x0)
+		for i := range y {
+			if y[i] != y0[i] {
+				t.Fatalf("mod %d: b * x != rhs at %d", field.Mod(), i)
+			}
+		}
+
+		rhs := AlignedMake[uint32](10)
+		rhs[9] = 1
+		if _, ok := Solve(field, b, rhs); ok {
+			t.Fatalf("mod %d: inconsistent system solved", field.Mod())
+		}
+	}
+
+	ring := NewRingZ2k(32)
+	a := invertible(ring, 16)
+	inv, ok := Inverse(ring, a)
+	if !ok || !MatMul(ring, a, inv).Equal(IdentityMatrix(16)) {
+		t.Fatalf("Z/2^32: a * a^-1 != I")
+	}
+}
+
+// Test views and layout conversions against the row-major entries
+func TestMatrixLayouts(t *testing.T) {
+	field := NewPrimeField(65537)
+	m := NewMatrix(12, 20)
+	copy(m.Data, field.SampleVector(12*20))
+
+	blocked := m.ToBlockRowMajor(4)
+	sliced := m.ToSlicedRowMajor(3)
+	for i := uint32(0); i < m.Rows; i++ {
+		for j := uint32(0); j < m.Cols; j++ {
+			if blocked.At(i, j) != m.At(i, j) || sliced.At(i, j) != m.At(i, j) {
+				t.Fatalf("entry (%d, %d) moved by the conversion", i, j)
+			}
+			if blocked.Column(j).At(i, 0) != m.At(i, j) || blocked.Block(j/5).At(i, j%5) != m.At(i, j) ||
+				sliced.Block(i/4).At(i%4, j) != m.At(i, j) {
+				t.Fatalf("view mismatch at (%d, %d)", i, j)
+			}
+		}
+	}
+	if blocked.Block(1).Data[0] != blocked.Data[12*5] {
+		t.Fatalf("block view does not share storage")
+	}
+	if !blocked.ToRowMajor().Equal(m) || !sliced.ToRowMajor().Equal(m) || !blocked.ToSlicedRowMajor(3).Equal(sliced) {
+		t.Fatalf("layout round trip changed the matrix")
+	}
+
+	view := blocked.SubMatrix(2, 6, 5, 3)
+	view.Set(0, 0, 7)
+	if blocked.At(2, 6) != 7 || view.At(4, 2) != m.At(6, 8) {
+		t.Fatalf("sub-matrix view mismatch")
+	}
+
+	bits := NewMatrix(40, 9)
+	for i := range bits.Data {
+		bits.Data[i] = uint32(i*7) % 3 % 2
+	}
+	packed := bits.ToPackedColMajor()
+	if packed.Rows != 9 || packed.Cols != 2 || !packed.ToRowMajor().SubMatrix(0, 0, 40, 9).Copy().Equal(bits) {
+		t.Fatalf("packing round trip changed the matrix")
+	}
+}
+
+// Test that a saved matrix reads back with its metadata and that mismatches and corruption are refused
+func TestMatrixFile(t *testing.T) {
+	field := NewPrimeField(65537)
+	m := NewMatrix(9, 24)
+	copy(m.Data, field.SampleVector(9*24))
+	m = m.ToBlockRowMajor(3)
+	m.Modulus = 65537
+	params := []Param{UintParam("S", 3), {Name: "Field", Value: "prime"}}
+
+	var buf bytes.Buffer
+	if err := WriteMatrix(&buf, "Test", params, m); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	file := buf.Bytes()
+	h, err := ReadMatrixHeader(bytes.NewReader(file))
+	if err != nil || uint64(h.HeaderLen)%ALIGNMENT != 0 || uint64(len(file)) != uint64(h.HeaderLen)+h.PayloadLen {
+		t.Fatalf("bad header %+v: %v", h, err)
+	}
+
+	r, err := ReadMatrix(bytes.NewReader(file), "Test", params)
+	if err != nil || !r.Equal(m) || r.Modulus != 65537 {
+		t.Fatalf("round trip changed the matrix: %v", err)
+	}
+
+	if _, err := ReadMatrix(bytes.NewReader(file), "Other", params); err == nil {
+		t.Fatalf("file of another scheme accepted")
+	}
+	if _, err := ReadMatrix(bytes.NewReader(file), "Test", []Param{UintParam("S", 4), params[1]}); err == nil ||
+		!strings.Contains(err.Error(), "S = 3") {
+		t.Fatalf("mismatched params accepted: %v", err)
+	}
+
+	corrupt := append([]byte{}, file...)
+	corrupt[len(corrupt)-1] ^= 1
+	if _, err := ReadMatrix(bytes.NewReader(corrupt), "Test", params); err == nil {
+		t.Fatalf("corrupted payload accepted")
+	}
+	if _, err := ReadMatrix(bytes.NewReader(file[:len(file)-4]), "Test", params); err == nil {
+		t.Fatalf("truncated payload accepted")
+	}
+}
+
+// Test that a mapped matrix sees the saved entries in place and that Verify catches corruption
+func TestMapMatrix(t *testing.T) {
+	m := NewMatrix(33, 40)
+	copy(m.Data, NewPrimeField(65537).SampleVector(33*40))
+	m = m.ToSlicedRowMajor(3)
+	path := t.TempDir() + "/m.db"
+	if err := SaveMatrix(path, "Test", nil, m); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	mm, err := MapMatrix(path, "Test", nil)
+	if err != nil {
+		t.Fatalf("map: %v", err)
+	}
+	if !mm.Equal(m) || mm.Verify() != nil || uint64(uintptr(unsafe.Pointer(&mm.Data[0])))%ALIGNMENT != 0 {
+		t.Fatalf("mapped matrix differs from the saved one")
+	}
+	if err := mm.Close(); err != nil || mm.Verify() == nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	file, _ := os.ReadFile(path)
+	file[len(file)-1] ^= 1
+	os.WriteFile(path, file, 0o644)
+	if mm, err = MapMatrix(path, "Test", nil); err != nil || mm.Verify() == nil {
+		t.Fatalf("corruption not detected: %v", err)
+	}
+	mm.Close()
+
+	os.WriteFile(path, file[:len(file)-8], 0o644)
+	if _, err := MapMatrix(path, "Test", nil); err == nil {
+		t.Fatalf("truncated file mapped")
+	}
+}
@@ -1,7 +1,10 @@
+//go:build cgo && !purego
+
 #include "fields.h"
 #include "mod_simd.h"
 #include <iostream>
 #include <stdint.h>
+#include <vector>
 
 inline void NoSimdAddVectors(uint32_t* r, const uint32_t* a, const uint32_t* b, uint64_t length, uint32_t p) {
     for (uint64_t i = 0; i < length; ++i) {
@@ -33,8 +36,113 @@ inline void NoSimdNegVector(uint32_t* r, uint64_t length, uint32_t p) {
     }
 }
 
+inline void NoSimdRingAddVectors(uint32_t* r, const uint32_t* a, const uint32_t* b, uint64_t length, uint32_t mask) {
+    for (uint64_t i = 0; i < length; ++i) {
+        r[i] = (a[i] + b[i]) & mask;
+    }
+}
+
+inline void NoSimdRingMulVector(uint32_t* r, const uint32_t* a, uint32_t b, uint64_t length, uint32_t mask) {
+    for (uint64_t i = 0; i < length; ++i) {
+        r[i] = (a[i] * b) & mask;
+    }
+}
+
+inline void NoSimdRingSubVectors(uint32_t* r, const uint32_t* a, const uint32_t* b, uint64_t length, uint32_t mask) {
+    for (uint64_t i = 0; i < length; ++i) {
+        r[i] = (a[i] - b[i]) & mask;
+    }
+}
+
+inline void NoSimdRingNegVector(uint32_t* r, uint64_t length, uint32_t mask) {
+    for (uint64_t i = 0; i < length; ++i) {
+        r[i] = (0U - r[i]) & mask;
+    }
+}
+
+// Carry-less product of two polynomials over GF(2) whose product has degree < 64
+inline uint64_t SoftClmul(uint64_t a, uint64_t b) {
+    uint64_t r = 0;
+    for (; b; b >>= 1, a <<= 1) {
+        if (b & 1) r ^= a;
+    }
+    return r;
+}
+
+#ifdef __PCLMUL__
+inline uint64_t HardClmul(uint64_t a, uint64_t b) {
+    __m128i va = _mm_cvtsi64_si128((long long)a);
+    __m128i vb = _mm_cvtsi64_si128((long long)b);
+    return (uint64_t)_mm_cvtsi128_si64(_mm_clmulepi64_si128(va, vb, 0x00));
+}
+#define Clmul HardClmul
+#else
+#define Clmul SoftClmul
+#endif
+
+// Barrett reduction over GF(2): for c of degree < 2k the quotient is exactly ((c >> k) * mu) >> k
+inline uint32_t BinaryFieldReduce(uint64_t c, uint32_t k, uint64_t poly, uint64_t mu) {
+    uint64_t q = Clmul(c >> k, mu) >> k;
+    uint64_t mask = (k == 32) ? 0xFFFFFFFFULL : ((1ULL << k) - 1);
+    return uint32_t((c ^ Clmul(q, poly)) & mask);
+}
+
+inline void NoSimdBinaryFieldAddVectors(uint32_t* r, const uint32_t* a, const uint32_t* b, uint64_t length) {
+    for (uint64_t i = 0; i < length; ++i) {
+        r[i] = a[i] ^ b[i];
+    }
+}
+
+inline void BinaryFieldMulVectorImpl(uint32_t* r, const uint32_t* a, uint32_t b, uint64_t length, uint32_t k, uint64_t poly, uint64_t mu) {
+    for (uint64_t i = 0; i < length; ++i) {
+        r[i] = BinaryFieldReduce(Clmul(a[i], b), k, poly, mu);
+    }
+}
+
+inline void ExtensionFieldMulVectorImpl(uint32_t* r, const uint32_t* a, const uint32_t* b, uint64_t length, uint32_t d, const uint32_t* modulus, uint32_t p) {
+    // negated modulus so that x^d = sum negf[j] x^j
+    std::vector<uint64_t> negf(d);
+    for (uint32_t j = 0; j < d; ++j) {
+        negf[j] = (uint64_t(p) - modulus[j]) % p;
+    }
+    std::vector<uint64_t> t(2 * d - 1);
+
+    for (uint64_t e = 0; e < length; ++e) {
+        const uint32_t* ae = a + e * d;
+        std::fill(t.begin(), t.end(), 0);
+
+        // schoolbook product, every partial sum stays below p^2 + p < 2^64
+        for (uint32_t i = 0; i < d; ++i) {
+            if (ae[i] == 0) continue;
+            for (uint32_t j = 0; j < d; ++j) {
+                t[i + j] = (t[i + j] + uint64_t(ae[i]) * b[j]) % p;
+            }
+        }
+
+        // fold the high coefficients back with the modulus
+        for (uint32_t i = 2 * d - 2; i >= d; --i) {
+            uint64_t c = t[i];
+            if (c == 0) continue;
+            for (uint32_t j = 0; j < d; ++j) {
+                t[i - d + j] = (t[i - d + j] + negf[j] * c) % p;
+            }
+        }
+
+        uint32_t* re = r + e * d;
+        for (uint32_t i = 0; i < d; ++i) {
+            re[i] = uint32_t(t[i]);
+        }
+    }
+}
+
 #ifdef __SSE2__
 inline void SSE2AddVectors(uint32_t* r, const uint32_t* a, const uint32_t* b, uint64_t length, uint32_t p) {
+    if (p > (1U << 31)) {
+        // a + b and a + p - b no longer fit into 32 bits
+        NoSimdAddVectors(r, a, b, length, p);
+        return;
+    }
+
     uint64_t i = 0;
 
     // Process 4 elements at a time
@@ -60,7 +168,8 @@ inline void SSE2MulVector(uint32_t* r, const uint32_t* a, uint32_t b, uint64_t l
     // Process 4 elements at a time
     _sse2_fermat_prime op(p);
     if (!op.valid()) {
-        // std::cerr << "========================================= " << p << std::endl;
+        // Only Fermat primes have a SIMD reduction
+        NoSimdMulVector(r, a, b, length, p);
         return;
     }
     __m128i mask = op.get_mask();
@@ -92,7 +201,8 @@ inline void SSE2MulVectors(uint32_t* r, const uint32_t* a, const uint32_t* b, ui
     // Process 4 elements at a time
     _sse2_fermat_prime op(p);
     if (!op.valid()) {
-        // std::cerr << "========================================= " << p << std::endl;
+        // Only Fermat primes have a SIMD reduction
+        NoSimdMulVectors(r, a, b, length, p);
         return;
     }
     __m128i mask = op.get_mask();
@@ -118,6 +228,12 @@ inline void SSE2MulVectors(uint32_t* r, const uint32_t* a, const uint32_t* b, ui
 }
 
 inline void SSE2SubVectors(uint32_t* r, const uint32_t* a, const uint32_t* b, uint64_t length, uint32_t p) {
+    if (p > (1U << 31)) {
+        // a + b and a + p - b no longer fit into 32 bits
+        NoSimdSubVectors(r, a, b, length, p);
+        return;
+    }
+
     uint64_t i = 0;
 
     // Subtract 4 elements at a time
@@ -160,6 +276,12 @@ inline void SSE2NegVector(uint32_t* r, uint64_t length, uint32_t p) {
 
 #ifdef __AVX2__
 inline void AVX2AddVectors(uint32_t* r, const uint32_t* a, const uint32_t* b, uint64_t length, uint32_t p) {
+    if (p > (1U << 31)) {
+        // a + b and a + p - b no longer fit into 32 bits
+        NoSimdAddVectors(r, a, b, length, p);
+        return;
+    }
+
     uint64_t i = 0;
 
     // Process 8 elements at a time
@@ -179,7 +301,7 @@ inline void AVX2AddVectors(uint32_t* r, const uint32_t* a, const uint32_t* b, ui
 }
 
 inline void AVX2MulVector(uint32_t* r, const uint32_t* a, uint32_t b, uint64_t length, uint32_t p) {
-    if (!r || !a || !b) {
+    if (!r || !a) {
         return;
     }
 
@@ -189,7 +311,8 @@ inline void AVX2MulVector(uint32_t* r, const uint32_t* a, uint32_t b, uint64_t l
     // Process 8 elements at a time
     _avx2_fermat_prime op(p);
     if (!op.init()) {
-        // std::cerr << "========================================= " << p << std::endl;
+        // Only Fermat primes have a SIMD reduction
+        NoSimdMulVector(r, a, b, length, p);
         return;
     }
     __m256i mask = op.get_mask();
@@ -243,7 +366,8 @@ inline void AVX2MulVectors(uint32_t* r, const uint32_t* a, const uint32_t* b, ui
     // Process 8 elements at a time
     _avx2_fermat_prime op(p);
     if (!op.init()) {
-        // std::cerr << "========================================= " << p << std::endl;
+        // Only Fermat primes have a SIMD reduction
+        NoSimdMulVectors(r, a, b, length, p);
         return;
     }
     __m256i mask = op.get_mask();
@@ -289,6 +413,12 @@ inline void AVX2MulVectors(uint32_t* r, const uint32_t* a, const uint32_t* b, ui
 }
 
 inline void AVX2SubVectors(uint32_t* r, const uint32_t* a, const uint32_t* b, uint64_t length, uint32_t p) {
+    if (p > (1U << 31)) {
+        // a + b and a + p - b no longer fit into 32 bits
+        NoSimdSubVectors(r, a, b, length, p);
+        return;
+    }
+
     uint64_t i = 0;
 
     // Subtract 8 elements at a time
@@ -327,6 +457,94 @@ inline void AVX2NegVector(uint32_t* r, uint64_t length, uint32_t p) {
 
     vector_mod_op(r, r, p, length);
 }
+
+inline void AVX2BinaryFieldAddVectors(uint32_t* r, const uint32_t* a, const uint32_t* b, uint64_t length) {
+    uint64_t i = 0;
+
+    // Addition in characteristic 2 is XOR, 8 elements at a time
+    for (; i + 8 <= length; i += 8) {
+        __m256i va = _mm256_loadu_si256((__m256i*)(a + i));
+        __m256i vb = _mm256_loadu_si256((__m256i*)(b + i));
+        _mm256_storeu_si256((__m256i*)(r + i), _mm256_xor_si256(va, vb));
+    }
+
+    // Handle remaining elements
+    for (; i < length; ++i) {
+        r[i] = a[i] ^ b[i];
+    }
+}
+
+inline void AVX2RingAddVectors(uint32_t* r, const uint32_t* a, const uint32_t* b, uint64_t length, uint32_t mask) {
+    uint64_t i = 0;
+
+    // Process 8 elements at a time, the 32-bit lanes wrap modulo 2^32
+    __m256i vmask = _mm256_set1_epi32(mask);
+    for (; i + 8 <= length; i += 8) {
+        __m256i va = _mm256_loadu_si256((__m256i*)(a + i));
+        __m256i vb = _mm256_loadu_si256((__m256i*)(b + i));
+        __m256i vr = _mm256_and_si256(_mm256_add_epi32(va, vb), vmask);
+        _mm256_storeu_si256((__m256i*)(r + i), vr);
+    }
+
+    // Handle remaining elements
+    for (; i < length; ++i) {
+        r[i] = (a[i] + b[i]) & mask;
+    }
+}
+
+inline void AVX2RingMulVector(uint32_t* r, const uint32_t* a, uint32_t b, uint64_t length, uint32_t mask) {
+    uint64_t i = 0;
+
+    // Low 32 bits of each product is exactly the product modulo 2^32
+    __m256i vmask = _mm256_set1_epi32(mask);
+    __m256i vb = _mm256_set1_epi32(b);
+    for (; i + 8 <= length; i += 8) {
+        __m256i va = _mm256_loadu_si256((__m256i*)(a + i));
+        __m256i vr = _mm256_and_si256(_mm256_mullo_epi32(va, vb), vmask);
+        _mm256_storeu_si256((__m256i*)(r + i), vr);
+    }
+
+    // Handle remaining elements
+    for (; i < length; ++i) {
+        r[i] = (a[i] * b) & mask;
+    }
+}
+
+inline void AVX2RingSubVectors(uint32_t* r, const uint32_t* a, const uint32_t* b, uint64_t length, uint32_t mask) {
+    uint64_t i = 0;
+
+    // Subtract 8 elements at a time
+    __m256i vmask = _mm256_set1_epi32(mask);
+    for (; i + 8 <= length; i += 8) {
+        __m256i va = _mm256_loadu_si256((__m256i*)(a + i));
+        __m256i vb = _mm256_loadu_si256((__m256i*)(b + i));
+        __m256i vr = _mm256_and_si256(_mm256_sub_epi32(va, vb), vmask);
+        _mm256_storeu_si256((__m256i*)(r + i), vr);
+    }
+
+    // Handle remaining scalars
+    for (; i < length; ++i) {
+        r[i] = (a[i] - b[i]) & mask;
+    }
+}
+
+inline void AVX2RingNegVector(uint32_t* r, uint64_t length, uint32_t mask) {
+    uint64_t i = 0;
+
+    // Negate 8 elements at a time
+    __m256i vmask = _mm256_set1_epi32(mask);
+    __m256i vzero = _mm256_setzero_si256();
+    for (; i + 8 <= length; i += 8) {
+        __m256i vr = _mm256_loadu_si256((__m256i*)(r + i));
+        __m256i vt = _mm256_and_si256(_mm256_sub_epi32(vzero, vr), vmask);
+        _mm256_storeu_si256((__m256i*)(r + i), vt);
+    }
+
+    // Handle remaining scalars
+    for (; i < length; ++i) {
+        r[i] = (0U - r[i]) & mask;
+    }
+}
 #endif
 
 extern "C" {
@@ -404,4 +622,145 @@ void FieldNegVector(
 #endif
 }
 
+void RingAddVectors(
+    uint32_t* r, uint64_t ro,
+    const uint32_t* a, uint64_t ao,
+    const uint32_t* b, uint64_t bo,
+    uint64_t length, uint32_t mask
+) {
+#if defined(__AVX2__)
+    AVX2RingAddVectors(r + ro, a + ao, b + bo, length, mask);
+#else
+    NoSimdRingAddVectors(r + ro, a + ao, b + bo, length, mask);
+#endif
+}
+
+void RingMulVector(
+    uint32_t* r, uint64_t ro,
+    const uint32_t* a, uint64_t ao,
+    uint32_t b,
+    uint64_t length, uint32_t mask
+) {
+#if defined(__AVX2__)
+    AVX2RingMulVector(r + ro, a + ao, b, length, mask);
+#else
+    NoSimdRingMulVector(r + ro, a + ao, b, length, mask);
+#endif
+}
+
+void RingSubVectors(
+    uint32_t* r, uint64_t ro,
+    const uint32_t* a, uint64_t ao,
+    const uint32_t* b, uint64_t bo,
+    uint64_t length, uint32_t mask
+) {
+#if defined(__AVX2__)
+    AVX2RingSubVectors(r + ro, a + ao, b + bo, length, mask);
+#else
+    NoSimdRingSubVectors(r + ro, a + ao, b + bo, length, mask);
+#endif
+}
+
+void RingNegVector(
+    uint32_t* r, uint64_t ro,
+    uint64_t length, uint32_t mask
+) {
+#if defined(__AVX2__)
+    AVX2RingNegVector(r + ro, length, mask);
+#else
+    NoSimdRingNegVector(r + ro, length, mask);
+#endif
+}
+
+void BinaryFieldAddVectors(
+    uint32_t* r, uint64_t ro,
+    const uint32_t* a, uint64_t ao,
+    const uint32_t* b, uint64_t bo,
+    uint64_t length
+) {
+#if defined(__AVX2__)
+    AVX2BinaryFieldAddVectors(r + ro, a + ao, b + bo, length);
+#else
+    NoSimdBinaryFieldAddVectors(r + ro, a + ao, b + bo, length);
+#endif
+}
+
+void BinaryFieldMulVector(
+    uint32_t* r, uint64_t ro,
+    const uint32_t* a, uint64_t ao,
+    uint32_t b,
+    uint64_t length, uint32_t k, uint64_t poly, uint64_t mu
+) {
+    BinaryFieldMulVectorImpl(r + ro, a + ao, b, length, k, poly, mu);
+}
+
+void ExtensionFieldMulVector(
+    uint32_t* r, uint64_t ro,
+    const uint32_t* a, uint64_t ao,
+    const uint32_t* b,
+    uint64_t length, uint32_t d, const uint32_t* modulus, uint32_t p
+) {
+    ExtensionFieldMulVectorImpl(r + ro * d, a + ao * d, b, length, d, modulus, p);
+}
+
+// a + b and a - b are computed in 128 bits so that moduli above 2^63 do not overflow
+void Field64AddVectors(
+    uint64_t* r, uint64_t ro,
+    const uint64_t* a, uint64_t ao,
+    const uint64_t* b, uint64_t bo,
+    uint64_t length, uint64_t p
+) {
+    r += ro; a += ao; b += bo;
+    for (uint64_t i = 0; i < length; ++i) {
+        unsigned __int128 s = (unsigned __int128)a[i] + b[i];
+        r[i] = uint64_t(s >= p ? s - p : s);
+    }
+}
+
+void Field64MulVector(
+    uint64_t* r, uint64_t ro,
+    const uint64_t* a, uint64_t ao,
+    uint64_t b,
+    uint64_t length, uint64_t p
+) {
+    r += ro; a += ao;
+    for (uint64_t i = 0; i < length; ++i) {
+        r[i] = uint64_t(((unsigned __int128)a[i] * b) % p);
+    }
+}
+
+void Field64MulVectors(
+    uint64_t* r, uint64_t ro,
+    const uint64_t* a, uint64_t ao,
+    const uint64_t* b, uint64_t bo,
+    uint64_t length, uint64_t p
+) {
+    r += ro; a += ao; b += bo;
+    for (uint64_t i = 0; i < length; ++i) {
+        r[i] = uint64_t(((unsigned __int128)a[i] * b[i]) % p);
+    }
+}
+
+void Field64SubVectors(
+    uint64_t* r, uint64_t ro,
+    const uint64_t* a, uint64_t ao,
+    const uint64_t* b, uint64_t bo,
+    uint64_t length, uint64_t p
+) {
+    r += ro; a += ao; b += bo;
+    for (uint64_t i = 0; i < length; ++i) {
+        r[i] = a[i] >= b[i] ? a[i] - b[i] : uint64_t((unsigned __int128)a[i] + p - b[i]);
+    }
+}
+
+void Field64NegVector(
+    uint64_t* r, uint64_t ro,
+    uint64_t length, uint64_t p
+) {
+    r += ro;
+    for (uint64_t i = 0; i < length; ++i) {
+        r[i] = r[i] == 0 ? 0 : p - r[i];
+    }
+}
+
 }
\ No newline at end of file
@@ -40,6 +40,91 @@ void FieldNegVector(
     uint64_t length, uint32_t p
 );
 
+// Z_2^k kernels: arithmetic wraps at 2^32 and is truncated with mask = 2^k - 1
+void RingAddVectors(
+    uint32_t* r, uint64_t ro,
+    const uint32_t* a, uint64_t ao,
+    const uint32_t* b, uint64_t bo,
+    uint64_t length, uint32_t mask
+);
+
+void RingMulVector(
+    uint32_t* r, uint64_t ro,
+    const uint32_t* a, uint64_t ao,
+    uint32_t b,
+    uint64_t length, uint32_t mask
+);
+
+void RingSubVectors(
+    uint32_t* r, uint64_t ro,
+    const uint32_t* a, uint64_t ao,
+    const uint32_t* b, uint64_t bo,
+    uint64_t length, uint32_t mask
+);
+
+void RingNegVector(
+    uint32_t* r, uint64_t ro,
+    uint64_t length, uint32_t mask
+);
+
+// GF(2^k) kernels: poly is the degree k irreducible polynomial and mu = floor(x^(2k) / poly)
+void BinaryFieldAddVectors(
+    uint32_t* r, uint64_t ro,
+    const uint32_t* a, uint64_t ao,
+    const uint32_t* b, uint64_t bo,
+    uint64_t length
+);
+
+void BinaryFieldMulVector(
+    uint32_t* r, uint64_t ro,
+    const uint32_t* a, uint64_t ao,
+    uint32_t b,
+    uint64_t length, uint32_t k, uint64_t poly, uint64_t mu
+);
+
+// GF(p^d) kernel on element-major limb vectors, offsets and length count elements of d limbs,
+// modulus holds the low d coefficients of the monic irreducible polynomial
+void ExtensionFieldMulVector(
+    uint32_t* r, uint64_t ro,
+    const uint32_t* a, uint64_t ao,
+    const uint32_t* b,
+    uint64_t length, uint32_t d, const uint32_t* modulus, uint32_t p
+);
+
+// 64-bit prime field kernels for moduli up to 2^64, products are formed in 128 bits
+void Field64AddVectors(
+    uint64_t* r, uint64_t ro,
+    const uint64_t* a, uint64_t ao,
+    const uint64_t* b, uint64_t bo,
+    uint64_t length, uint64_t p
+);
+
+void Field64MulVector(
+    uint64_t* r, uint64_t ro,
+    const uint64_t* a, uint64_t ao,
+    uint64_t b,
+    uint64_t length, uint64_t p
+);
+
+void Field64MulVectors(
+    uint64_t* r, uint64_t ro,
+    const uint64_t* a, uint64_t ao,
+    const uint64_t* b, uint64_t bo,
+    uint64_t length, uint64_t p
+);
+
+void Field64SubVectors(
+    uint64_t* r, uint64_t ro,
+    const uint64_t* a, uint64_t ao,
+    const uint64_t* b, uint64_t bo,
+    uint64_t length, uint64_t p
+);
+
+void Field64NegVector(
+    uint64_t* r, uint64_t ro,
+    uint64_t length, uint64_t p
+);
+
 #ifdef __cplusplus
 }
 #endif
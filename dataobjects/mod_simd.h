@@ -7,7 +7,8 @@
 #include <stdint.h>
 #include <stddef.h>
 
-#define _MOD_SIMD_ALIGNED_LOADSTORE
+// Callers pass arbitrary vector offsets, so the loads and stores must not assume alignment
+// #define _MOD_SIMD_ALIGNED_LOADSTORE
 
 const uint32_t _not_prime_power = ~0U;
 
@@ -0,0 +1,160 @@
+// Package difftest is a differential test oracle for the vector and matrix kernels. Every kernel is run
+// on random inputs through its fast path (the C kernels, or their purego ports) and through a reference
+// path, and the outputs are compared element by element. The reference path is the Go loop selected by
+// dataobjects.ReferenceBackend where the kernel has one, and a straightforward implementation on top of
+// the scalar field operations otherwise.
+package difftest
+
+import (
+	"RandomLinearCodePIR/dataobjects"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+)
+
+// At most this many mismatches are kept per kernel, Report.Count has the total
+const MaxRecordedMismatches = 16
+
+type Config struct {
+	Seed   int64
+	Trials int
+	// Vector length, a power of two so that it can also be used as an NTT size. Matrices are N/8 x N.
+	N uint32
+}
+
+func DefaultConfig() Config {
+	return Config{Seed: 1, Trials: 4, N: 512}
+}
+
+type Mismatch struct {
+	Trial     int
+	Index     int
+	Fast      uint64
+	Reference uint64
+}
+
+type Report struct {
+	Kernel     string
+	Trials     int
+	Count      int
+	Mismatches []Mismatch
+}
+
+func (r Report) OK() bool { return r.Count == 0 }
+
+func (r Report) String() string {
+	if r.OK() {
+		return fmt.Sprintf("%-48s ok (%d trials)", r.Kernel, r.Trials)
+	}
+	m := r.Mismatches[0]
+	return fmt.Sprintf("%-48s %d mismatches, first at trial %d index %d: fast %d, reference %d",
+		r.Kernel, r.Count, m.Trial, m.Index, m.Fast, m.Reference)
+}
+
+// A kernel runs one trial and returns the outputs of the fast and the reference path
+type kernel struct {
+	name string
+	run  func(rng *rand.Rand, n uint32) (fast, reference []uint64)
+}
+
+var kernels []kernel
+
+func register(name string, run func(rng *rand.Rand, n uint32) (fast, reference []uint64)) {
+	kernels = append(kernels, kernel{name: name, run: run})
+}
+
+// Kernels returns the names of all registered kernels
+func Kernels() []string {
+	names := make([]string, len(kernels))
+	for i, k := range kernels {
+		names[i] = k.name
+	}
+	return names
+}
+
+// Run checks every kernel and returns one report per kernel in registration order
+func Run(config Config) []Report {
+	reports := make([]Report, 0, len(kernels))
+	for _, k := range kernels {
+		reports = append(reports, runKernel(k, config))
+	}
+	return reports
+}
+
+// RunKernel checks a single kernel by name
+func RunKernel(name string, config Config) Report {
+	for _, k := range kernels {
+		if k.name == name {
+			return runKernel(k, config)
+		}
+	}
+	panic("unknown kernel " + name)
+}
+
+func runKernel(k kernel, config Config) Report {
+	if config.N < 64 || config.N&(config.N-1) != 0 {
+		panic("N must be a power of two of at least 64")
+	}
+
+	// Kernels get independent streams so that adding one does not change the inputs of the others
+	h := fnv.New64a()
+	h.Write([]byte(k.name))
+	rng := rand.New(rand.NewSource(config.Seed ^ int64(h.Sum64())))
+
+	report := Report{Kernel: k.name, Trials: config.Trials}
+	for trial := 0; trial < config.Trials; trial++ {
+		fast, reference := k.run(rng, config.N)
+		if len(fast) != len(reference) {
+			panic(fmt.Sprintf("%s: fast and reference outputs differ in length", k.name))
+		}
+		for i := range fast {
+			if fast[i] == reference[i] {
+				continue
+			}
+			report.Count++
+			if len(report.Mismatches) < MaxRecordedMismatches {
+				report.Mismatches = append(report.Mismatches, Mismatch{Trial: trial, Index: i, Fast: fast[i], Reference: reference[i]})
+			}
+		}
+	}
+
+	return report
+}
+
+// withBackend runs fn under the given backend and restores the previous one
+func withBackend(b dataobjects.Backend, fn func()) {
+	prev := dataobjects.SetBackend(b)
+	defer dataobjects.SetBackend(prev)
+	fn()
+}
+
+// bothBackends runs fn once per backend, fn must not keep its output between the calls
+func bothBackends(fn func() []uint64) (fast, reference []uint64) {
+	withBackend(dataobjects.FastBackend, func() { fast = fn() })
+	withBackend(dataobjects.ReferenceBackend, func() { reference = fn() })
+	return fast, reference
+}
+
+func widen(v []uint32) []uint64 {
+	w := make([]uint64, len(v))
+	for i := range v {
+		w[i] = uint64(v[i])
+	}
+	return w
+}
+
+func randomVector(rng *rand.Rand, n uint32, field dataobjects.Field) []uint32 {
+	v := dataobjects.AlignedMake[uint32](uint64(n))
+	for i := range v {
+		v[i] = field.SampleElementWithSeed(rng)
+	}
+	return v
+}
+
+func randomVector64(rng *rand.Rand, n uint32, field dataobjects.Field64) []uint64 {
+	v := dataobjects.AlignedMake[uint64](uint64(n))
+	for i := range v {
+		v[i] = field.SampleElementWithSeed(rng)
+	}
+	return v
+}
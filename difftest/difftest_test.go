@@ -0,0 +1,18 @@
+package difftest
+
+import (
+	"testing"
+)
+
+// Every kernel must agree with its reference path
+func TestDifferential(t *testing.T) {
+	config := DefaultConfig()
+	config.Trials = 2
+	config.N = 256
+
+	for _, report := range Run(config) {
+		if !report.OK() {
+			t.Errorf("%s", report)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package difftest
+
+import (
+	"RandomLinearCodePIR/dataobjects"
+	"fmt"
+	"math/rand"
+)
+
+// Offsets into the vectors so that the ro/ao/bo handling of the kernels is covered
+const (
+	offR = 3
+	offA = 5
+	offB = 7
+)
+
+type namedField struct {
+	name  string
+	field func() dataobjects.Field
+}
+
+var vectorFields = []namedField{
+	{"PrimeField(65537)", func() dataobjects.Field { return dataobjects.NewPrimeField(65537) }},
+	{"PrimeField(2147483647)", func() dataobjects.Field { return dataobjects.NewPrimeField(2147483647) }},
+	{"PrimeField(4294967291)", func() dataobjects.Field { return dataobjects.NewPrimeField(4294967291) }},
+	{"RingZ2k(16)", func() dataobjects.Field { return dataobjects.NewRingZ2k(16) }},
+	{"RingZ2k(32)", func() dataobjects.Field { return dataobjects.NewRingZ2k(32) }},
+	{"BinaryField(8)", func() dataobjects.Field { return dataobjects.NewBinaryField(8) }},
+	{"BinaryField(32)", func() dataobjects.Field { return dataobjects.NewBinaryField(32) }},
+	{"ExtensionField(257^3)", func() dataobjects.Field { return dataobjects.NewExtensionField(257, 3) }},
+}
+
+func init() {
+	for _, nf := range vectorFields {
+		field := nf.field()
+
+		register(fmt.Sprintf("dataobjects.%s.AddVectors", nf.name), func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+			a, b := randomVector(rng, n+offA, field), randomVector(rng, n+offB, field)
+			return bothBackends(func() []uint64 {
+				r := dataobjects.AlignedMake[uint32](uint64(n + offR))
+				field.AddVectors(r, offR, a, offA, b, offB, uint64(n))
+				return widen(r)
+			})
+		})
+
+		register(fmt.Sprintf("dataobjects.%s.SubVectors", nf.name), func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+			a, b := randomVector(rng, n+offA, field), randomVector(rng, n+offB, field)
+			return bothBackends(func() []uint64 {
+				r := dataobjects.AlignedMake[uint32](uint64(n + offR))
+				field.SubVectors(r, offR, a, offA, b, offB, uint64(n))
+				return widen(r)
+			})
+		})
+
+		register(fmt.Sprintf("dataobjects.%s.MulVector", nf.name), func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+			a, b := randomVector(rng, n+offA, field), field.SampleElementWithSeed(rng)
+			return bothBackends(func() []uint64 {
+				r := dataobjects.AlignedMake[uint32](uint64(n + offR))
+				field.MulVector(r, offR, a, offA, b, uint64(n))
+				return widen(r)
+			})
+		})
+
+		register(fmt.Sprintf("dataobjects.%s.NegVector", nf.name), func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+			a := randomVector(rng, n+offR, field)
+			return bothBackends(func() []uint64 {
+				r := dataobjects.AlignedMake[uint32](uint64(n + offR))
+				copy(r, a)
+				field.NegVector(r, offR, uint64(n))
+				return widen(r)
+			})
+		})
+	}
+
+	ef := dataobjects.NewExtensionField(65537, 2)
+	register("dataobjects.ExtensionField(65537^2).MulLimbVector", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		d := ef.Degree()
+		a := randomVector(rng, (n+offA)*d, ef.Base())
+		b := randomVector(rng, d, ef.Base())
+		return bothBackends(func() []uint64 {
+			r := dataobjects.AlignedMake[uint32](uint64((n + offR) * d))
+			ef.MulLimbVector(r, offR, a, offA, b, uint64(n))
+			return widen(r)
+		})
+	})
+
+	// 2^60 - 2^18 + 1
+	f64 := dataobjects.NewPrimeField64(1152921504606584833)
+	register("dataobjects.PrimeField64.AddVectors", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		a, b := randomVector64(rng, n+offA, f64), randomVector64(rng, n+offB, f64)
+		return bothBackends(func() []uint64 {
+			r := dataobjects.AlignedMake[uint64](uint64(n + offR))
+			f64.AddVectors(r, offR, a, offA, b, offB, uint64(n))
+			return r
+		})
+	})
+
+	register("dataobjects.PrimeField64.SubVectors", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		a, b := randomVector64(rng, n+offA, f64), randomVector64(rng, n+offB, f64)
+		return bothBackends(func() []uint64 {
+			r := dataobjects.AlignedMake[uint64](uint64(n + offR))
+			f64.SubVectors(r, offR, a, offA, b, offB, uint64(n))
+			return r
+		})
+	})
+
+	register("dataobjects.PrimeField64.MulVector", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		a, b := randomVector64(rng, n+offA, f64), f64.SampleElementWithSeed(rng)
+		return bothBackends(func() []uint64 {
+			r := dataobjects.AlignedMake[uint64](uint64(n + offR))
+			f64.MulVector(r, offR, a, offA, b, uint64(n))
+			return r
+		})
+	})
+
+	register("dataobjects.PrimeField64.NegVector", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		a := randomVector64(rng, n+offR, f64)
+		return bothBackends(func() []uint64 {
+			r := dataobjects.AlignedMake[uint64](uint64(n + offR))
+			copy(r, a)
+			f64.NegVector(r, offR, uint64(n))
+			return r
+		})
+	})
+}
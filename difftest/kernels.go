@@ -0,0 +1,478 @@
+package difftest
+
+import (
+	"RandomLinearCodePIR/dataobjects"
+	"RandomLinearCodePIR/ecc"
+	"RandomLinearCodePIR/mvp"
+	"RandomLinearCodePIR/pir"
+	"RandomLinearCodePIR/tdm"
+	"RandomLinearCodePIR/utils"
+	"crypto/aes"
+	"encoding/binary"
+	"math/rand"
+)
+
+// The 32-bit MVP kernels accumulate without reduction, so p is kept small enough that N (p-1)^2 < 2^64
+const (
+	mvpPrime = 786433 // 3 * 2^18 + 1
+	nttPrime = 65537
+	rsPrime  = 65537
+	prime64  = 1152921504606584833
+)
+
+func init() {
+	registerMVP()
+	registerTDM()
+	registerECC()
+	registerPIR()
+	registerUtils()
+}
+
+func registerMVP() {
+	field := dataobjects.NewPrimeField(mvpPrime)
+	f64 := dataobjects.NewPrimeField64(prime64)
+	const s = 4
+
+	register("mvp.MatVecProduct", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		rows := n / 8
+		mat, vec := randomVector(rng, rows*n, field), randomVector(rng, n, field)
+		out := dataobjects.AlignedMake[uint32](uint64(rows))
+		mvp.MatVecProduct(mat, vec, out, rows, n, mvpPrime)
+		return widen(out), widen(matVec(field, mat, vec, rows, n))
+	})
+
+	register("mvp.TransformToBlockwise", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		rows := n / 8
+		m := &dataobjects.Matrix{Rows: rows, Cols: n, Data: randomVector(rng, rows*n, field)}
+		blocked := dataobjects.AlignedMake[uint32](uint64(rows * n))
+		mvp.TransformToBlockwise(m.Data, blocked, rows, n, s)
+		return widen(blocked), widen(m.ToBlockRowMajor(s).Data)
+	})
+
+	register("mvp.TransformFromBlockwise", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		rows := n / 8
+		m := &dataobjects.Matrix{Rows: rows, Cols: n, Data: randomVector(rng, rows*n, field), Layout: dataobjects.BlockRowMajor, Blocks: s}
+		out := dataobjects.AlignedMake[uint32](uint64(rows * n))
+		mvp.TransformFromBlockwise(m.Data, out, rows, n, s)
+		return widen(out), widen(m.ToRowMajor().Data)
+	})
+
+	register("mvp.BlockMatVecProduct", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		rows, b := n/8, n/s
+		mat, vec := randomVector(rng, rows*n, field), randomVector(rng, n, field)
+		blocked := dataobjects.AlignedMake[uint32](uint64(rows * n))
+		mvp.TransformToBlockwise(mat, blocked, rows, n, s)
+		out := dataobjects.AlignedMake[uint32](uint64(s * rows))
+		mvp.BlockMatVecProduct(blocked, vec, out, rows, n, s, mvpPrime)
+
+		reference := make([]uint32, 0, s*rows)
+		for blk := uint32(0); blk < s; blk++ {
+			sub := make([]uint32, rows*b)
+			for r := uint32(0); r < rows; r++ {
+				copy(sub[r*b:(r+1)*b], mat[r*n+blk*b:r*n+(blk+1)*b])
+			}
+			reference = append(reference, matVec(field, sub, vec[blk*b:(blk+1)*b], rows, b)...)
+		}
+		return widen(out), widen(reference)
+	})
+
+	register("mvp.BlockMatMatProduct", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		rows, q := n/8, uint32(3)
+		mat, vecs := randomVector(rng, rows*n, field), randomVector(rng, q*n, field)
+		blocked := dataobjects.AlignedMake[uint32](uint64(rows * n))
+		mvp.TransformToBlockwise(mat, blocked, rows, n, s)
+		out := dataobjects.AlignedMake[uint32](uint64(q * s * rows))
+		mvp.BlockMatMatProduct(blocked, vecs, out, rows, n, s, q, mvpPrime)
+
+		reference := dataobjects.AlignedMake[uint32](uint64(q * s * rows))
+		for v := uint32(0); v < q; v++ {
+			mvp.BlockMatVecProduct(blocked, vecs[v*n:(v+1)*n], reference[v*s*rows:], rows, n, s, mvpPrime)
+		}
+		return widen(out), widen(reference)
+	})
+
+	register("mvp.BlockVecMatProduct", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		rows, cols := n/8, n
+		mat, vec := randomVector(rng, rows*cols, field), randomVector(rng, rows, field)
+		out := dataobjects.AlignedMake[uint32](uint64(2 * cols))
+		mvp.BlockVecMatProduct(mat, vec, out, rows, cols, 2, mvpPrime)
+
+		reference := make([]uint32, 2*cols)
+		for blk := uint32(0); blk < 2; blk++ {
+			for r := blk * rows / 2; r < (blk+1)*rows/2; r++ {
+				for c := uint32(0); c < cols; c++ {
+					reference[blk*cols+c] = field.Add(reference[blk*cols+c], field.Mul(vec[r], mat[r*cols+c]))
+				}
+			}
+		}
+		return widen(out), widen(reference)
+	})
+
+	register("mvp.MatVecProduct64", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		rows := n / 8
+		mat, vec := randomVector64(rng, rows*n, f64), randomVector64(rng, n, f64)
+		out := dataobjects.AlignedMake[uint64](uint64(rows))
+		mvp.MatVecProduct64(mat, vec, out, rows, n, prime64)
+		return out, matVec64(f64, mat, vec, rows, n)
+	})
+
+	register("mvp.BlockMatVecProduct64", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		rows, b := n/8, n/s
+		mat, vec := randomVector64(rng, rows*n, f64), randomVector64(rng, n, f64)
+		blocked := dataobjects.AlignedMake[uint64](uint64(rows * n))
+		mvp.TransformToBlockwise64(mat, blocked, rows, n, s)
+		out := dataobjects.AlignedMake[uint64](uint64(s * rows))
+		mvp.BlockMatVecProduct64(blocked, vec, out, rows, n, s, prime64)
+
+		reference := make([]uint64, 0, s*rows)
+		for blk := uint32(0); blk < s; blk++ {
+			sub := make([]uint64, rows*b)
+			for r := uint32(0); r < rows; r++ {
+				copy(sub[r*b:(r+1)*b], mat[r*n+blk*b:r*n+(blk+1)*b])
+			}
+			reference = append(reference, matVec64(f64, sub, vec[blk*b:(blk+1)*b], rows, b)...)
+		}
+		return out, reference
+	})
+
+	register("mvp.BlockVecMatProduct64", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		rows, cols := n/8, n
+		mat, vec := randomVector64(rng, rows*cols, f64), randomVector64(rng, rows, f64)
+		out := dataobjects.AlignedMake[uint64](uint64(2 * cols))
+		mvp.BlockVecMatProduct64(mat, vec, out, rows, cols, 2, prime64)
+
+		reference := make([]uint64, 2*cols)
+		for blk := uint32(0); blk < 2; blk++ {
+			for r := blk * rows / 2; r < (blk+1)*rows/2; r++ {
+				for c := uint32(0); c < cols; c++ {
+					reference[blk*cols+c] = f64.Add(reference[blk*cols+c], f64.Mul(vec[r], mat[r*cols+c]))
+				}
+			}
+		}
+		return out, reference
+	})
+}
+
+func registerTDM() {
+	field := dataobjects.NewPrimeField(nttPrime)
+	f64 := dataobjects.NewPrimeField64(prime64)
+
+	register("tdm.NTT", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		a := randomVector(rng, n, field)
+		root := tdm.NthRootOfUnity(nttPrime, n)
+		reference := dft(field, a, root)
+		tdm.NTT(a, n, root, nttPrime)
+		return widen(a), widen(reference)
+	})
+
+	register("tdm.NTT_Convolution", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		a, b := randomVector(rng, n, field), randomVector(rng, n, field)
+		out := dataobjects.AlignedMake[uint32](uint64(n))
+		tdm.NTT_Convolution(a, b, out, n, tdm.NthRootOfUnity(nttPrime, n), nttPrime)
+
+		reference := make([]uint32, n)
+		for i := uint32(0); i < n; i++ {
+			for j := uint32(0); j < n; j++ {
+				reference[(i+j)%n] = field.Add(reference[(i+j)%n], field.Mul(a[i], b[j]))
+			}
+		}
+		return widen(out), widen(reference)
+	})
+
+	register("tdm.NTT64", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		a := randomVector64(rng, n, f64)
+		root := tdm.NthRootOfUnity64(prime64, uint64(n))
+
+		reference := make([]uint64, n)
+		for k := uint32(0); k < n; k++ {
+			w := pow64(f64, root, uint64(k))
+			x := uint64(1)
+			for j := uint32(0); j < n; j++ {
+				reference[k] = f64.Add(reference[k], f64.Mul(a[j], x))
+				x = f64.Mul(x, w)
+			}
+		}
+		tdm.NTT64(a, n, root, prime64)
+		return a, reference
+	})
+
+	register("tdm.NTT_Convolution64", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		a, b := randomVector64(rng, n, f64), randomVector64(rng, n, f64)
+		out := dataobjects.AlignedMake[uint64](uint64(n))
+		tdm.NTT_Convolution64(a, b, out, n, tdm.NthRootOfUnity64(prime64, uint64(n)), prime64)
+
+		reference := make([]uint64, n)
+		for i := uint32(0); i < n; i++ {
+			for j := uint32(0); j < n; j++ {
+				reference[(i+j)%n] = f64.Add(reference[(i+j)%n], f64.Mul(a[i], b[j]))
+			}
+		}
+		return out, reference
+	})
+
+	// The circulant polynomial is drawn from a different stream per backend, so each backend is
+	// checked against its own explicit circulant matrix
+	register("tdm.CirculantVectorMul", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		v := randomVector(rng, n, field)
+		seed := rng.Int63()
+		root := tdm.NthRootOfUnity(nttPrime, n)
+
+		var fast, reference []uint64
+		for _, b := range []dataobjects.Backend{dataobjects.FastBackend, dataobjects.ReferenceBackend} {
+			withBackend(b, func() {
+				fast = append(fast, widen(tdm.CirculantVectorMul(n, nttPrime, root, seed, v))...)
+				S := tdm.GetCirculantMatrix(n, nttPrime, seed)
+				for _, row := range S {
+					reference = append(reference, uint64(dot(field, row, v)))
+				}
+			})
+		}
+		return fast, reference
+	})
+
+	register("tdm.EvaluationCircuit", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		rows, cols := uint32(32), uint32(64)
+		v := randomVector(rng, cols, field)
+		td := tdm.TDM{M: rows, N: cols, Q: nttPrime,
+			SeedL: rng.Int63n(1 << 40), SeedPL: rng.Int63n(1 << 40), SeedC: rng.Int63n(1 << 40),
+			SeedPR: rng.Int63n(1 << 40), SeedR: rng.Int63n(1 << 40)}
+
+		var fast, reference []uint64
+		for _, b := range []dataobjects.Backend{dataobjects.FastBackend, dataobjects.ReferenceBackend} {
+			withBackend(b, func() {
+				fast = append(fast, widen(td.EvaluationCircuit(v))...)
+				reference = append(reference, widen(matVec(field, td.GenerateFlattenedTrapDooredMatrix(), v, rows, cols))...)
+			})
+		}
+		return fast, reference
+	})
+}
+
+func registerECC() {
+	field := dataobjects.NewPrimeField(rsPrime)
+
+	register("ecc.GenerateSystematicRSMatrix", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		k, length := n/16, n/4
+		alphas := distinctElements(rng, length, rsPrime)
+		out := dataobjects.AlignedMake[uint32](uint64(length * k))
+		ecc.GenerateSystematicRSMatrix(length, k, rsPrime, alphas, out)
+
+		reference := make([]uint32, length*k)
+		for row := uint32(0); row < length; row++ {
+			for col := uint32(0); col < k; col++ {
+				reference[row*k+col] = lagrangeBasis(field, alphas[:k], col, alphas[row])
+			}
+		}
+		return widen(out), widen(reference)
+	})
+
+	register("ecc.LagrangeInterpEval", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		k := n / 16
+		x := distinctElements(rng, k, rsPrime)
+		y := randomVector(rng, k, field)
+
+		var fast, reference []uint64
+		for t := 0; t < 8; t++ {
+			point := uint32(rng.Intn(rsPrime))
+			fast = append(fast, uint64(ecc.LagrangeInterpEval(x, y, k, point, rsPrime)))
+
+			acc := uint32(0)
+			for i := uint32(0); i < k; i++ {
+				acc = field.Add(acc, field.Mul(y[i], lagrangeBasis(field, x, i, point)))
+			}
+			reference = append(reference, uint64(acc))
+		}
+		return fast, reference
+	})
+}
+
+func registerPIR() {
+	register("pir.VecMatrixMulF2", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		rows, cols := n/8, n/4
+		mat, vec := randomWords(rng, rows*cols), randomBits(rng, rows)
+		out := make([]uint32, cols)
+		pir.VecMatrixMulF2(out, mat, vec, rows, cols)
+		return widen(out), widen(xorRows(mat, vec, 0, rows, cols))
+	})
+
+	register("pir.MatrixColXORByBlock", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		rows, cols, block := n/8, n/4, uint32(16)
+		mat, vec := randomWords(rng, rows*cols), randomBits(rng, rows)
+		out := make([]uint32, rows/block*cols)
+		pir.MatrixColXORByBlock(vec, mat, out, rows, cols, block)
+
+		var reference []uint32
+		for k := uint32(0); k < rows/block; k++ {
+			reference = append(reference, xorRows(mat, vec, k*block, (k+1)*block, cols)...)
+		}
+		return widen(out), widen(reference)
+	})
+
+	register("pir.MatrixColXORByBlock2D", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		// rows is not a multiple of the block size to cover the partial last block
+		rows, cols, block := n/8+5, n/4, uint32(24)
+		mat := randomWords(rng, rows*cols)
+		bits1, bits2 := randomBits(rng, rows), randomBits(rng, rows)
+		nblocks := (rows + block - 1) / block
+		out1, out2 := make([]uint32, nblocks*cols), make([]uint32, nblocks*cols)
+		pir.MatrixColXORByBlock2D(packBits(bits1), packBits(bits2), mat, out1, out2, rows, cols, block)
+
+		var reference []uint32
+		for _, bits := range [][]uint32{bits1, bits2} {
+			for k := uint32(0); k < nblocks; k++ {
+				reference = append(reference, xorRows(mat, bits, k*block, min((k+1)*block, rows), cols)...)
+			}
+		}
+		return widen(append(out1, out2...)), widen(reference)
+	})
+}
+
+func registerUtils() {
+	// The native generator is AES-128 in counter mode, checked against crypto/aes
+	register("utils.RandomizeVectorWithSeed", func(rng *rand.Rand, n uint32) ([]uint64, []uint64) {
+		length := n + uint32(rng.Intn(4))
+		seed := rng.Int63() - rng.Int63()
+		out := make([]uint32, length)
+		utils.RandomizeVectorWithSeed(out, length, seed)
+
+		var key [16]byte
+		binary.LittleEndian.PutUint64(key[:8], uint64(seed))
+		binary.LittleEndian.PutUint64(key[8:], uint64(seed))
+		block, err := aes.NewCipher(key[:])
+		if err != nil {
+			panic(err)
+		}
+
+		reference := make([]uint32, 0, length+3)
+		var ctr, buf [16]byte
+		for counter := uint64(1); uint32(len(reference)) < length; counter++ {
+			binary.LittleEndian.PutUint64(ctr[:8], counter)
+			block.Encrypt(buf[:], ctr[:])
+			for j := 0; j < 16; j += 4 {
+				reference = append(reference, binary.LittleEndian.Uint32(buf[j:]))
+			}
+		}
+		return widen(out), widen(reference[:length])
+	})
+}
+
+func dot(field dataobjects.Field, a, b []uint32) uint32 {
+	acc := uint32(0)
+	for i := range a {
+		acc = field.Add(acc, field.Mul(a[i], b[i]))
+	}
+	return acc
+}
+
+func matVec(field dataobjects.Field, mat, vec []uint32, rows, cols uint32) []uint32 {
+	out := make([]uint32, rows)
+	for r := uint32(0); r < rows; r++ {
+		out[r] = dot(field, mat[r*cols:(r+1)*cols], vec[:cols])
+	}
+	return out
+}
+
+func matVec64(field dataobjects.Field64, mat, vec []uint64, rows, cols uint32) []uint64 {
+	out := make([]uint64, rows)
+	for r := uint32(0); r < rows; r++ {
+		for c := uint32(0); c < cols; c++ {
+			out[r] = field.Add(out[r], field.Mul(mat[r*cols+c], vec[c]))
+		}
+	}
+	return out
+}
+
+// a[k] = sum_j a[j] * root^(jk)
+func dft(field dataobjects.Field, a []uint32, root uint32) []uint32 {
+	n := uint32(len(a))
+	out := make([]uint32, n)
+	for k := uint32(0); k < n; k++ {
+		w := pow(field, root, k)
+		x := uint32(1)
+		for j := uint32(0); j < n; j++ {
+			out[k] = field.Add(out[k], field.Mul(a[j], x))
+			x = field.Mul(x, w)
+		}
+	}
+	return out
+}
+
+func pow(field dataobjects.Field, a, e uint32) uint32 {
+	r := uint32(1)
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			r = field.Mul(r, a)
+		}
+		a = field.Mul(a, a)
+	}
+	return r
+}
+
+func pow64(field dataobjects.Field64, a, e uint64) uint64 {
+	r := uint64(1)
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			r = field.Mul(r, a)
+		}
+		a = field.Mul(a, a)
+	}
+	return r
+}
+
+// L_i(x) = prod_{j != i} (x - x_j) / (x_i - x_j)
+func lagrangeBasis(field dataobjects.Field, nodes []uint32, i, x uint32) uint32 {
+	num, den := uint32(1), uint32(1)
+	for j := range nodes {
+		if uint32(j) != i {
+			num = field.Mul(num, field.Sub(x, nodes[j]))
+			den = field.Mul(den, field.Sub(nodes[i], nodes[j]))
+		}
+	}
+	return field.Mul(num, field.Inv(den))
+}
+
+func distinctElements(rng *rand.Rand, n, p uint32) []uint32 {
+	perm := rng.Perm(int(p))
+	out := make([]uint32, n)
+	for i := range out {
+		out[i] = uint32(perm[i])
+	}
+	return out
+}
+
+func randomWords(rng *rand.Rand, n uint32) []uint32 {
+	v := make([]uint32, n)
+	for i := range v {
+		v[i] = rng.Uint32()
+	}
+	return v
+}
+
+func randomBits(rng *rand.Rand, n uint32) []uint32 {
+	v := make([]uint32, n)
+	for i := range v {
+		v[i] = uint32(rng.Intn(2))
+	}
+	return v
+}
+
+func packBits(bits []uint32) []uint32 {
+	packed := make([]uint32, (len(bits)+31)/32)
+	for i, b := range bits {
+		packed[i/32] |= b << (i % 32)
+	}
+	return packed
+}
+
+// XOR of the rows in [from, to) of mat selected by bits
+func xorRows(mat, bits []uint32, from, to, cols uint32) []uint32 {
+	out := make([]uint32, cols)
+	for r := from; r < to; r++ {
+		if bits[r] == 1 {
+			for c := uint32(0); c < cols; c++ {
+				out[c] ^= mat[r*cols+c]
+			}
+		}
+	}
+	return out
+}
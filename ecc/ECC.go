@@ -1,7 +1,25 @@
 package ecc
 
+import (
+	"RandomLinearCodePIR/dataobjects"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
 const (
-	ReedSolomon = "ReedSolomon"
+	ReedSolomon          = "ReedSolomon"
+	ExtensionReedSolomon = "ExtensionReedSolomon"
+	// Reed-Solomon with an errors-and-erasures decoder
+	ReedSolomonGao = "ReedSolomonGao"
+	// Reed-Solomon on a power of two subgroup, encoded and decoded with NTTs
+	NTTReedSolomon = "NTTReedSolomon"
+	// Systematic code with random parity rows
+	RandomLinear = "RandomLinear"
+	// Sparse parity checks with a peeling decoder
+	LDPC = "LDPC"
 )
 
 type ECCConfig struct {
@@ -9,18 +27,219 @@ type ECCConfig struct {
 	Q    uint32
 	N    uint32
 	K    uint32
+	// Extension degree d for codes over GF(Q^d)
+	Degree uint32
+	// Seed of the randomly generated codes, encoder and decoder must agree on it
+	Seed int64
+	// Evaluation points of ReedSolomon and ReedSolomonGao, one per symbol and pairwise distinct mod Q. The
+	// default is 0, ..., N-1; SubgroupPoints and CosetPoints build structured sets.
+	Points []uint32
 }
 
+// ErasureCorrectionCode is a systematic code of length N() over Field() whose codewords start with the
+// K() message symbols. Symbols of a code over an extension field are stored as Degree limbs each.
 type ErasureCorrectionCode interface {
-	GetGeneratorMatrix(k, n, p uint32) []uint32
+	K() uint32
+	N() uint32
+	Field() dataobjects.Field
+	// MinDistance returns d, so that any d - 1 erasures can be corrected. Codes whose distance is expensive
+	// to compute return a lower bound.
+	MinDistance() uint32
+	// Encode returns the codeword of message
+	Encode(message []uint32) []uint32
+	// GetGeneratorMatrix returns the (N - K) x K parity part of the generator, row-major
+	GetGeneratorMatrix() []uint32
 	Decode(code []uint32, noisyIndicator []bool) ([]uint32, error)
+	// DecodeBatch decodes codewords sharing one erasure pattern, the messages are written into the front of
+	// each codeword like Decode does
+	DecodeBatch(codes [][]uint32, erasures []bool) ([][]uint32, error)
+}
+
+// ECCConstructor builds a code from its config, returning an error if the config does not describe one
+type ECCConstructor func(config ECCConfig) (ErasureCorrectionCode, error)
+
+var (
+	registryLock sync.RWMutex
+	registry     = map[string]ECCConstructor{}
+)
+
+// RegisterECC makes a code available to NewECCCode and GetECCCode under name, so that ECCConfig.Name (and
+// LpnParams.ECCName) can select codes defined outside this package. Registering a name twice panics.
+func RegisterECC(name string, constructor ECCConstructor) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if _, ok := registry[name]; ok {
+		panic("ECC code registered twice: " + name)
+	}
+	registry[name] = constructor
+}
+
+// RegisteredECCs returns the names of all registered codes in sorted order
+func RegisteredECCs() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
+func NewECCCode(config ECCConfig) (ErasureCorrectionCode, error) {
+	registryLock.RLock()
+	constructor, ok := registry[config.Name]
+	registryLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported ECC code %q", config.Name)
+	}
+	return constructor(config)
+}
+
+// GetECCCode is NewECCCode for configs known to be valid, it panics on an error
 func GetECCCode(config ECCConfig) ErasureCorrectionCode {
-	switch config.Name {
-	case ReedSolomon:
-		return NewReedSolomonCode(config.K, config.N, config.Q)
-	default:
-		panic("Unsupported ECC code: " + config.Name)
+	code, err := NewECCCode(config)
+	if err != nil {
+		panic(err)
+	}
+	return code
+}
+
+func init() {
+	RegisterECC(ReedSolomon, func(c ECCConfig) (ErasureCorrectionCode, error) {
+		points, err := evaluationPoints(c)
+		if err != nil {
+			return nil, err
+		}
+		return NewReedSolomonCodeWithPoints(c.K, c.N, c.Q, points), nil
+	})
+	RegisterECC(ReedSolomonGao, func(c ECCConfig) (ErasureCorrectionCode, error) {
+		points, err := evaluationPoints(c)
+		if err != nil {
+			return nil, err
+		}
+		return NewGaoReedSolomonCodeWithPoints(c.K, c.N, c.Q, points), nil
+	})
+	RegisterECC(NTTReedSolomon, func(c ECCConfig) (ErasureCorrectionCode, error) {
+		if err := checkDimensions(c, uint64(c.Q)); err != nil {
+			return nil, err
+		}
+		if size := nttDomainSize(c.K, c.N); (c.Q-1)%size != 0 {
+			return nil, fmt.Errorf("F_%d has no root of unity of order %d", c.Q, size)
+		}
+		return NewNTTReedSolomonCode(c.K, c.N, c.Q), nil
+	})
+	RegisterECC(RandomLinear, func(c ECCConfig) (ErasureCorrectionCode, error) {
+		if err := checkDimensions(c, math.MaxUint32); err != nil {
+			return nil, err
+		}
+		return NewRandomLinearCode(c.K, c.N, c.Q, c.Seed), nil
+	})
+	RegisterECC(LDPC, func(c ECCConfig) (ErasureCorrectionCode, error) {
+		if err := checkDimensions(c, math.MaxUint32); err != nil {
+			return nil, err
+		}
+		return NewLDPCCode(c.K, c.N, c.Q, c.Seed), nil
+	})
+	RegisterECC(ExtensionReedSolomon, func(c ECCConfig) (ErasureCorrectionCode, error) {
+		if c.Degree == 0 {
+			return nil, errors.New("the extension degree must be positive")
+		}
+		order := math.Pow(float64(c.Q), float64(c.Degree))
+		if err := checkDimensions(c, uint64(min(order, math.MaxUint32))); err != nil {
+			return nil, err
+		}
+		return NewExtensionReedSolomonCode(c.K, c.N, dataobjects.NewExtensionField(c.Q, c.Degree)), nil
+	})
+}
+
+// evaluationPoints validates the config of a Reed-Solomon code and returns its points
+func evaluationPoints(config ECCConfig) ([]uint32, error) {
+	if err := checkDimensions(config, uint64(config.Q)); err != nil {
+		return nil, err
+	}
+	if len(config.Points) == 0 {
+		return getAlphas(config.N), nil
+	}
+	if uint32(len(config.Points)) != config.N {
+		return nil, fmt.Errorf("%d evaluation points for a code of length %d", len(config.Points), config.N)
+	}
+	if err := CheckPoints(config.Q, config.Points); err != nil {
+		return nil, err
+	}
+	return config.Points, nil
+}
+
+// checkDimensions returns an error unless 1 <= K <= N <= maxN and Q is at least 2
+func checkDimensions(config ECCConfig, maxN uint64) error {
+	if config.Q < 2 {
+		return fmt.Errorf("invalid field size %d", config.Q)
+	}
+	if len(config.Points) > 0 && config.Name != ReedSolomon && config.Name != ReedSolomonGao {
+		return fmt.Errorf("%s code does not take evaluation points", config.Name)
+	}
+	if config.K == 0 || config.K > config.N {
+		return fmt.Errorf("code needs 1 <= K <= N, got K = %d, N = %d", config.K, config.N)
+	}
+	if uint64(config.N) > maxN {
+		return fmt.Errorf("%s code of length %d needs more evaluation points than the field has", config.Name, config.N)
+	}
+	return nil
+}
+
+// encodeWithGenerator returns the codeword of message under the parity rows generator
+func encodeWithGenerator(field dataobjects.Field, generator, message []uint32, k, n uint32) []uint32 {
+	codeword := make([]uint32, n)
+	copy(codeword, message[:k])
+	for row := uint32(0); row < n-k; row++ {
+		sum := uint32(0)
+		for col := uint32(0); col < k; col++ {
+			sum = field.Add(sum, field.Mul(generator[row*k+col], message[col]))
+		}
+		codeword[k+row] = sum
+	}
+	return codeword
+}
+
+// decodingNodes returns the first k symbols that are not erased, which the decoders interpolate from, and
+// the erased message symbols
+func decodingNodes(erasures []bool, k uint32) (nodes, erased []uint32, err error) {
+	for i := range erasures {
+		if !erasures[i] && uint32(len(nodes)) < k {
+			nodes = append(nodes, uint32(i))
+		}
+		if erasures[i] && uint32(i) < k {
+			erased = append(erased, uint32(i))
+		}
+	}
+	if uint32(len(nodes)) < k {
+		return nil, nil, errors.New("Decoding Failed Due To Not Enough Data.")
+	}
+	return nodes, erased, nil
+}
+
+// applyDecodingMatrix sets symbol erased[r] of every codeword to sum_j decoding[r][j] * symbol nodes[j], as
+// one product of the decoding matrix with the matrix whose columns are the node symbols of the codewords
+func applyDecodingMatrix(field dataobjects.Field, decoding *dataobjects.Matrix, nodes, erased []uint32, codes [][]uint32) {
+	known := dataobjects.NewMatrix(uint32(len(nodes)), uint32(len(codes)))
+	for c, code := range codes {
+		for j, node := range nodes {
+			known.Set(uint32(j), uint32(c), code[node])
+		}
+	}
+	recovered := dataobjects.MatMul(field, decoding, known)
+	for c, code := range codes {
+		for r, i := range erased {
+			code[i] = recovered.At(uint32(r), uint32(c))
+		}
+	}
+}
+
+// messages returns the first k symbols of every codeword
+func messages(codes [][]uint32, k uint32) [][]uint32 {
+	result := make([][]uint32, len(codes))
+	for c := range codes {
+		result[c] = codes[c][:k]
 	}
+	return result
 }
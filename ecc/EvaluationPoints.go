@@ -0,0 +1,49 @@
+package ecc
+
+import (
+	"RandomLinearCodePIR/tdm"
+	"fmt"
+)
+
+// CheckPoints returns an error unless the points are pairwise distinct elements of F_q
+func CheckPoints(q uint32, points []uint32) error {
+	seen := make(map[uint32]int, len(points))
+	for i, a := range points {
+		if a >= q {
+			return fmt.Errorf("evaluation point %d = %d is not reduced mod %d", i, a, q)
+		}
+		if j, ok := seen[a]; ok {
+			return fmt.Errorf("evaluation points %d and %d are both %d", j, i, a)
+		}
+		seen[a] = i
+	}
+	return nil
+}
+
+// SubgroupPoints returns the multiplicative subgroup of order n of F_q as w^0, ..., w^(n-1), where w is the
+// root of unity tdm.NthRootOfUnity picks, so that the points line up with tdm.NTT
+func SubgroupPoints(q, n uint32) ([]uint32, error) {
+	if n == 0 || (q-1)%n != 0 {
+		return nil, fmt.Errorf("F_%d has no subgroup of order %d", q, n)
+	}
+	return CosetPoints(q, n, 1)
+}
+
+// CosetPoints returns shift * w^i for i < n, the coset of the subgroup of order n through shift
+func CosetPoints(q, n, shift uint32) ([]uint32, error) {
+	if n == 0 || (q-1)%n != 0 {
+		return nil, fmt.Errorf("F_%d has no subgroup of order %d", q, n)
+	}
+	if shift%q == 0 {
+		return nil, fmt.Errorf("a coset needs a nonzero shift")
+	}
+
+	w := uint64(tdm.NthRootOfUnity(q, n))
+	points := make([]uint32, n)
+	x := uint64(shift % q)
+	for i := range points {
+		points[i] = uint32(x)
+		x = x * w % uint64(q)
+	}
+	return points, nil
+}
@@ -0,0 +1,201 @@
+package ecc
+
+import (
+	"RandomLinearCodePIR/dataobjects"
+	"errors"
+)
+
+// ExtensionReedSolomonCode is the systematic Reed-Solomon code over GF(q^d). The evaluation points
+// are the elements with index 0..n-1 (see ExtensionField.ElementFromIndex), so n can be as large as
+// q^d instead of q. Codewords, messages and generator entries use the element-major limb layout of
+// ExtensionField: a codeword of n symbols is a []uint32 of n * d limbs.
+type ExtensionReedSolomonCode struct {
+	k         uint32
+	n         uint32
+	field     *dataobjects.ExtensionField
+	generator []uint32
+}
+
+func NewExtensionReedSolomonCode(k, n uint32, field *dataobjects.ExtensionField) *ExtensionReedSolomonCode {
+	if k == 0 || k > n {
+		panic("Reed-Solomon code needs 1 <= k <= n")
+	}
+	if uint64(n) > field.Order() {
+		panic("Not enough evaluation points in the extension field")
+	}
+	rs := &ExtensionReedSolomonCode{
+		k:     k,
+		n:     n,
+		field: field,
+	}
+
+	// Row i of the evaluation part holds L_j(alpha_{k+i}) for j < k with d limbs per entry
+	d := field.Degree()
+	nodes := rs.points(0, k)
+	weights := barycentricWeights(field, nodes)
+	rs.generator = dataobjects.AlignedMake[uint32](uint64((n - k) * k * d))
+	for row := k; row < n; row++ {
+		basis := lagrangeBasisAt(field, nodes, weights, field.ElementFromIndex(uint64(row)))
+		copy(rs.generator[(row-k)*k*d:], basis)
+	}
+	return rs
+}
+
+func (rs *ExtensionReedSolomonCode) K() uint32           { return rs.k }
+func (rs *ExtensionReedSolomonCode) N() uint32           { return rs.n }
+func (rs *ExtensionReedSolomonCode) MinDistance() uint32 { return rs.n - rs.k + 1 }
+
+// Field returns the field through the Field interface, whose methods panic unless it is Packable.
+// Use ExtensionField for the limb API, which works for every p and d.
+func (rs *ExtensionReedSolomonCode) Field() dataobjects.Field { return rs.field }
+
+func (rs *ExtensionReedSolomonCode) ExtensionField() *dataobjects.ExtensionField { return rs.field }
+
+// Only return the evaluation part
+func (rs *ExtensionReedSolomonCode) GetGeneratorMatrix() []uint32 {
+	return rs.generator
+}
+
+// Encode takes k * d limbs and returns n * d limbs
+func (rs *ExtensionReedSolomonCode) Encode(message []uint32) []uint32 {
+	field := rs.field
+	d := field.Degree()
+	codeword := make([]uint32, rs.n*d)
+	copy(codeword, message[:rs.k*d])
+
+	term := field.NewElement()
+	for row := uint32(0); row < rs.n-rs.k; row++ {
+		symbol := codeword[(rs.k+row)*d : (rs.k+row+1)*d]
+		for col := uint32(0); col < rs.k; col++ {
+			entry := rs.generator[(row*rs.k+col)*d : (row*rs.k+col+1)*d]
+			field.MulElement(term, entry, message[col*d:(col+1)*d])
+			field.AddElement(symbol, symbol, term)
+		}
+	}
+	return codeword
+}
+
+func (rs *ExtensionReedSolomonCode) Decode(code []uint32, noisyQuery []bool) ([]uint32, error) {
+	field := rs.field
+	d := field.Degree()
+
+	if isAllFalse(noisyQuery[:rs.k]) {
+		return code[:rs.k*d], nil
+	}
+
+	nodes := make([][]uint32, 0, rs.k)
+	values := make([][]uint32, 0, rs.k)
+	for i := range noisyQuery {
+		if !noisyQuery[i] && uint32(len(nodes)) < rs.k {
+			nodes = append(nodes, field.ElementFromIndex(uint64(i)))
+			values = append(values, code[uint32(i)*d:uint32(i+1)*d])
+		}
+	}
+
+	if uint32(len(nodes)) < rs.k {
+		return []uint32{}, errors.New("Decoding Failed Due To Not Enough Data.")
+	}
+
+	weights := barycentricWeights(field, nodes)
+	term := field.NewElement()
+	for i := uint32(0); i < rs.k; i++ {
+		if !noisyQuery[i] {
+			continue
+		}
+
+		basis := lagrangeBasisAt(field, nodes, weights, field.ElementFromIndex(uint64(i)))
+		erased := code[i*d : (i+1)*d]
+		copy(erased, field.NewElement())
+		for j := range nodes {
+			field.MulElement(term, values[j], basis[uint32(j)*d:uint32(j+1)*d])
+			field.AddElement(erased, erased, term)
+		}
+	}
+
+	return code[:rs.k*d], nil
+}
+
+// DecodeBatch evaluates the Lagrange basis at the erased points once and applies it to all codewords
+func (rs *ExtensionReedSolomonCode) DecodeBatch(codes [][]uint32, erasures []bool) ([][]uint32, error) {
+	field := rs.field
+	d := field.Degree()
+
+	result := make([][]uint32, len(codes))
+	for c := range codes {
+		result[c] = codes[c][:rs.k*d]
+	}
+	if isAllFalse(erasures[:rs.k]) {
+		return result, nil
+	}
+	nodeIndices, erased, err := decodingNodes(erasures, rs.k)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([][]uint32, len(nodeIndices))
+	for j, i := range nodeIndices {
+		nodes[j] = field.ElementFromIndex(uint64(i))
+	}
+	weights := barycentricWeights(field, nodes)
+	bases := make([][]uint32, len(erased))
+	for r, i := range erased {
+		bases[r] = lagrangeBasisAt(field, nodes, weights, field.ElementFromIndex(uint64(i)))
+	}
+
+	term := field.NewElement()
+	for _, code := range codes {
+		for r, i := range erased {
+			symbol := code[i*d : (i+1)*d]
+			copy(symbol, field.NewElement())
+			for j, node := range nodeIndices {
+				field.MulElement(term, code[node*d:(node+1)*d], bases[r][uint32(j)*d:uint32(j+1)*d])
+				field.AddElement(symbol, symbol, term)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (rs *ExtensionReedSolomonCode) points(from, to uint32) [][]uint32 {
+	points := make([][]uint32, 0, to-from)
+	for i := from; i < to; i++ {
+		points = append(points, rs.field.ElementFromIndex(uint64(i)))
+	}
+	return points
+}
+
+// w_i = 1 / prod_{j != i} (x_i - x_j)
+func barycentricWeights(field *dataobjects.ExtensionField, nodes [][]uint32) [][]uint32 {
+	weights := make([][]uint32, len(nodes))
+	diff := field.NewElement()
+	for i := range nodes {
+		den := field.Embed(1)
+		for j := range nodes {
+			if i != j {
+				field.SubElement(diff, nodes[i], nodes[j])
+				field.MulElement(den, den, diff)
+			}
+		}
+		field.InvElement(den, den)
+		weights[i] = den
+	}
+	return weights
+}
+
+// Returns (L_0(x), ..., L_{k-1}(x)) as limbs with L_i(x) = w_i * prod_{j != i} (x - x_j)
+func lagrangeBasisAt(field *dataobjects.ExtensionField, nodes, weights [][]uint32, x []uint32) []uint32 {
+	d := field.Degree()
+	basis := make([]uint32, uint32(len(nodes))*d)
+	diff := field.NewElement()
+	for i := range nodes {
+		li := basis[uint32(i)*d : uint32(i+1)*d]
+		copy(li, weights[i])
+		for j := range nodes {
+			if i != j {
+				field.SubElement(diff, x, nodes[j])
+				field.MulElement(li, li, diff)
+			}
+		}
+	}
+	return basis
+}
@@ -0,0 +1,85 @@
+package ecc
+
+import (
+	"errors"
+	"math"
+)
+
+// Failure probability of the ECC layer of LpnMVP. Every slice query adds utils.RandomLPNNoiseVector(L, epsi)
+// to its codeword. A coordinate is noisy when it passes the rand.Float64() <= epsi test, so a slice is clean
+// only if all L coordinates fail it, and the slices are noisy independently with probability 1 - (1 - epsi)^L.
+// Decode fails once more slices are noisy than the code corrects.
+
+// Longest code SuggestECCLength tries
+const maxSuggestedLength = 1 << 24
+
+// SliceNoiseProbability returns the probability that a noise vector of length l is nonzero
+func SliceNoiseProbability(epsi float64, l uint32) float64 {
+	if epsi <= 0 {
+		return 0
+	}
+	if epsi >= 1 {
+		return 1
+	}
+	return -math.Expm1(float64(l) * math.Log1p(-epsi))
+}
+
+// ErasureFailureProbability returns the probability that more than tolerated of n symbols are erased when
+// each is erased independently with probability p, the upper tail of the binomial distribution
+func ErasureFailureProbability(n, tolerated uint32, p float64) float64 {
+	if tolerated >= n || p <= 0 {
+		return 0
+	}
+	if p >= 1 {
+		return 1
+	}
+
+	logP, logQ := math.Log(p), math.Log1p(-p)
+	lgN, _ := math.Lgamma(float64(n) + 1)
+	sum := 0.0
+	for j := tolerated + 1; j <= n; j++ {
+		lgJ, _ := math.Lgamma(float64(j) + 1)
+		lgRest, _ := math.Lgamma(float64(n-j) + 1)
+		sum += math.Exp(lgN - lgJ - lgRest + float64(j)*logP + float64(n-j)*logQ)
+	}
+	return min(sum, 1)
+}
+
+// DecodingFailureProbability returns the probability that LpnMVP fails to decode with an MDS code of
+// dimension m1 and length eccLength, such as the Reed-Solomon codes, i.e. that more than eccLength - m1
+// of the slices are noisy
+func DecodingFailureProbability(epsi float64, l, m1, eccLength uint32) float64 {
+	return ErasureFailureProbability(eccLength, eccLength-m1, SliceNoiseProbability(epsi, l))
+}
+
+// CodeFailureProbability bounds the failure probability of LpnMVP with the given code from above, using
+// that any MinDistance() - 1 noisy slices are corrected. It is exact for MDS codes.
+func CodeFailureProbability(code ErasureCorrectionCode, epsi float64, l uint32) float64 {
+	return ErasureFailureProbability(code.N(), code.MinDistance()-1, SliceNoiseProbability(epsi, l))
+}
+
+// SuggestECCLength returns the smallest eccLength whose DecodingFailureProbability is at most target. The
+// failure probability falls as the length grows, so the length is found by doubling and bisection.
+func SuggestECCLength(epsi float64, l, m1 uint32, target float64) (uint32, error) {
+	if m1 == 0 {
+		return 0, errors.New("the message length must be positive")
+	}
+	fails := func(n uint32) bool { return DecodingFailureProbability(epsi, l, m1, n) > target }
+
+	lo, hi := m1, m1
+	for fails(hi) {
+		if hi >= maxSuggestedLength {
+			return 0, errors.New("no code length reaches the target failure probability")
+		}
+		lo, hi = hi+1, min(2*hi, maxSuggestedLength)
+	}
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if fails(mid) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return hi, nil
+}
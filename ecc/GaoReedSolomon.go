@@ -0,0 +1,75 @@
+package ecc
+
+import "errors"
+
+// GaoReedSolomonCode is the code of ReedSolomonCode, with the same generator matrix, but Decode also
+// corrects errors at unknown positions using Gao's algorithm. Symbols flagged in noisyIndicator are
+// treated as erasures; a nil indicator means none. With e erasures up to (n - k - e) / 2 errors among the
+// remaining symbols are corrected.
+type GaoReedSolomonCode struct {
+	ReedSolomonCode
+}
+
+func NewGaoReedSolomonCode(k, n, q uint32) *GaoReedSolomonCode {
+	return NewGaoReedSolomonCodeWithPoints(k, n, q, getAlphas(n))
+}
+
+func NewGaoReedSolomonCodeWithPoints(k, n, q uint32, alphas []uint32) *GaoReedSolomonCode {
+	return &GaoReedSolomonCode{ReedSolomonCode: *NewReedSolomonCodeWithPoints(k, n, q, alphas)}
+}
+
+func (rs *GaoReedSolomonCode) Decode(code []uint32, noisyQuery []bool) ([]uint32, error) {
+	field := rs.field
+	alphas := rs.alphas
+
+	points := make([]uint32, 0, rs.n)
+	values := make([]uint32, 0, rs.n)
+	for i := uint32(0); i < rs.n; i++ {
+		if noisyQuery == nil || !noisyQuery[i] {
+			points = append(points, alphas[i])
+			values = append(values, code[i])
+		}
+	}
+	if uint32(len(points)) < rs.k {
+		return []uint32{}, errors.New("Decoding Failed Due To Not Enough Data.")
+	}
+
+	// Gao: run the extended Euclidean algorithm on g0 = prod (x - a_i) and the interpolant g1 until the
+	// remainder g = u * g0 + v * g1 has degree below (n' + k) / 2, then the message polynomial is g / v
+	g0 := vanishingPoly(field, points)
+	g1 := interpolate(field, points, values)
+	bound := len(points) + int(rs.k)
+
+	r0, r1 := g0, g1
+	v0, v1 := []uint32(nil), []uint32{1}
+	for 2*polyDegree(r1) >= bound {
+		q, r := polyDivMod(field, r0, r1)
+		r0, r1 = r1, r
+		v0, v1 = v1, polySub(field, v0, polyMul(field, q, v1))
+	}
+
+	f, rem := polyDivMod(field, r1, v1)
+	if len(rem) != 0 || polyDegree(f) >= int(rs.k) {
+		return []uint32{}, errors.New("Decoding Failed Due To Too Many Errors.")
+	}
+
+	// The code is systematic, the message is the codeword at the first k evaluation points
+	for i := uint32(0); i < rs.k; i++ {
+		code[i] = polyEval(field, f, alphas[i])
+	}
+	return code[:rs.k], nil
+}
+
+// DecodeBatch decodes the codewords one by one, the errors differ between codewords so there is no common
+// decoding matrix
+func (rs *GaoReedSolomonCode) DecodeBatch(codes [][]uint32, erasures []bool) ([][]uint32, error) {
+	result := make([][]uint32, len(codes))
+	for c := range codes {
+		message, err := rs.Decode(codes[c], erasures)
+		if err != nil {
+			return nil, err
+		}
+		result[c] = message
+	}
+	return result, nil
+}
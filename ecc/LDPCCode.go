@@ -0,0 +1,188 @@
+package ecc
+
+import (
+	"RandomLinearCodePIR/dataobjects"
+	"errors"
+	"math/rand"
+)
+
+// Number of message symbols summed into each parity symbol
+const ldpcCheckDegree = 3
+
+// LDPCCode is a systematic low-density code: parity symbol t is the sum of a few message symbols, chosen
+// from a seed so that every message symbol is covered. Encoding and peeling decoding take time linear in
+// the number of edges, but unlike an MDS code k received symbols are not always enough.
+type LDPCCode struct {
+	k     uint32
+	n     uint32
+	field *dataobjects.PrimeField
+	// checks[t] lists the message symbols summed into parity symbol k + t
+	checks [][]uint32
+}
+
+func NewLDPCCode(k, n, q uint32, seed int64) *LDPCCode {
+	if k == 0 || k > n {
+		panic("linear code needs 1 <= k <= n")
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	checks := make([][]uint32, n-k)
+	for j := uint32(0); j < k && len(checks) > 0; j++ {
+		t := j % uint32(len(checks))
+		checks[t] = append(checks[t], j)
+	}
+	for t := range checks {
+		for len(checks[t]) < min(ldpcCheckDegree, int(k)) {
+			j := uint32(rng.Intn(int(k)))
+			if !contains(checks[t], j) {
+				checks[t] = append(checks[t], j)
+			}
+		}
+	}
+
+	return &LDPCCode{
+		k:      k,
+		n:      n,
+		field:  dataobjects.NewPrimeField(q),
+		checks: checks,
+	}
+}
+
+func contains(s []uint32, v uint32) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (lc *LDPCCode) K() uint32                { return lc.k }
+func (lc *LDPCCode) N() uint32                { return lc.n }
+func (lc *LDPCCode) Field() dataobjects.Field { return lc.field }
+
+// MinDistance is a lower bound: every message symbol is in some check, so a nonzero message changes at
+// least two symbols
+func (lc *LDPCCode) MinDistance() uint32 {
+	if lc.n > lc.k {
+		return 2
+	}
+	return 1
+}
+
+// Only return the evaluation part
+func (lc *LDPCCode) GetGeneratorMatrix() []uint32 {
+	generator := make([]uint32, (lc.n-lc.k)*lc.k)
+	for t, check := range lc.checks {
+		for _, j := range check {
+			generator[uint32(t)*lc.k+j] = 1
+		}
+	}
+	return generator
+}
+
+func (lc *LDPCCode) Encode(message []uint32) []uint32 {
+	codeword := make([]uint32, lc.n)
+	copy(codeword, message[:lc.k])
+	for t, check := range lc.checks {
+		sum := uint32(0)
+		for _, j := range check {
+			sum = lc.field.Add(sum, message[j])
+		}
+		codeword[lc.k+uint32(t)] = sum
+	}
+	return codeword
+}
+
+func (lc *LDPCCode) Decode(code []uint32, noisyQuery []bool) ([]uint32, error) {
+	messages, err := lc.DecodeBatch([][]uint32{code}, noisyQuery)
+	if err != nil {
+		return []uint32{}, err
+	}
+	return messages[0], nil
+}
+
+// peelStep recovers message symbol symbol from the received parity symbol of check
+type peelStep struct {
+	check  uint32
+	symbol uint32
+}
+
+// DecodeBatch finds the peeling schedule once: a received parity whose check has a single erased message
+// symbol determines it, which may leave another check with a single erased symbol. The schedule is then
+// replayed on every codeword.
+func (lc *LDPCCode) DecodeBatch(codes [][]uint32, erasures []bool) ([][]uint32, error) {
+	if isAllFalse(erasures[:lc.k]) {
+		return messages(codes, lc.k), nil
+	}
+
+	// Checks touching each message symbol, and the number of erased symbols left in each usable check
+	touching := make([][]uint32, lc.k)
+	unknown := make([]int, len(lc.checks))
+	for t, check := range lc.checks {
+		if erasures[lc.k+uint32(t)] {
+			continue
+		}
+		for _, j := range check {
+			touching[j] = append(touching[j], uint32(t))
+			if erasures[j] {
+				unknown[t]++
+			}
+		}
+	}
+
+	erased := make([]bool, lc.k)
+	copy(erased, erasures[:lc.k])
+	remaining := 0
+	ready := []uint32{}
+	for t := range unknown {
+		if unknown[t] == 1 {
+			ready = append(ready, uint32(t))
+		}
+	}
+	for j := range erased {
+		if erased[j] {
+			remaining++
+		}
+	}
+
+	schedule := []peelStep{}
+	for len(ready) > 0 {
+		t := ready[len(ready)-1]
+		ready = ready[:len(ready)-1]
+		if unknown[t] != 1 {
+			continue
+		}
+		symbol := uint32(0)
+		for _, j := range lc.checks[t] {
+			if erased[j] {
+				symbol = j
+			}
+		}
+		schedule = append(schedule, peelStep{check: t, symbol: symbol})
+		erased[symbol] = false
+		remaining--
+		for _, u := range touching[symbol] {
+			unknown[u]--
+			if unknown[u] == 1 {
+				ready = append(ready, u)
+			}
+		}
+	}
+	if remaining > 0 {
+		return nil, errors.New("Decoding Failed Due To Stopping Set.")
+	}
+
+	for _, code := range codes {
+		for _, step := range schedule {
+			value := code[lc.k+step.check]
+			for _, j := range lc.checks[step.check] {
+				if j != step.symbol {
+					value = lc.field.Sub(value, code[j])
+				}
+			}
+			code[step.symbol] = value
+		}
+	}
+	return messages(codes, lc.k), nil
+}
@@ -0,0 +1,266 @@
+package ecc
+
+import (
+	"RandomLinearCodePIR/dataobjects"
+	"RandomLinearCodePIR/tdm"
+	"errors"
+	"math/bits"
+)
+
+// NTTReedSolomonCode is a systematic Reed-Solomon code evaluated on the multiplicative subgroup of order
+// size, a power of two dividing q - 1, so that encoding and erasure decoding run on NTTs in O(n log n).
+//
+// Domain index d stands for the point w^bitrev(d), which puts the subgroup of order sub, the smallest power
+// of two >= k, on the first sub indices. The message polynomial has degree < sub, takes the message values on
+// the first k points and zero on the next sub - k; those zeros are not transmitted (the code is shortened), so
+// symbol i < k sits at domain index i and parity symbol i >= k at domain index i + sub - k.
+type NTTReedSolomonCode struct {
+	k     uint32
+	n     uint32
+	q     uint32
+	field *dataobjects.PrimeField
+	size  uint32
+	sub   uint32
+	root  uint32
+	// Some element outside the subgroup, the decoder divides polynomials on the coset shift * <root>
+	shift uint32
+}
+
+func NewNTTReedSolomonCode(k, n, q uint32) *NTTReedSolomonCode {
+	if k == 0 || k > n {
+		panic("Reed-Solomon code needs 1 <= k <= n")
+	}
+	sub := nextPowerOfTwo(k)
+	size := nttDomainSize(k, n)
+	if (q-1)%size != 0 {
+		panic("NTT Reed-Solomon code needs a power of two root of unity of order >= n in the field")
+	}
+
+	rs := &NTTReedSolomonCode{
+		k:     k,
+		n:     n,
+		q:     q,
+		field: dataobjects.NewPrimeField(q),
+		size:  size,
+		sub:   sub,
+		root:  tdm.NthRootOfUnity(q, size),
+	}
+	for rs.shift = 2; fieldPow(rs.field, rs.shift, size) == 1; rs.shift++ {
+	}
+	return rs
+}
+
+// nttDomainSize returns the order of the subgroup the code of dimension k and length n is evaluated on
+func nttDomainSize(k, n uint32) uint32 {
+	return nextPowerOfTwo(n + nextPowerOfTwo(k) - k)
+}
+
+func (rs *NTTReedSolomonCode) K() uint32                { return rs.k }
+func (rs *NTTReedSolomonCode) N() uint32                { return rs.n }
+func (rs *NTTReedSolomonCode) Field() dataobjects.Field { return rs.field }
+func (rs *NTTReedSolomonCode) MinDistance() uint32      { return rs.n - rs.k + 1 }
+
+// domainIndex maps symbol i of the codeword to its evaluation point
+func (rs *NTTReedSolomonCode) domainIndex(i uint32) uint32 {
+	if i < rs.k {
+		return i
+	}
+	return i + rs.sub - rs.k
+}
+
+// Point returns the evaluation point of symbol i
+func (rs *NTTReedSolomonCode) Point(i uint32) uint32 {
+	return fieldPow(rs.field, rs.root, bitReverse(rs.domainIndex(i), rs.size))
+}
+
+// Only return the evaluation part, built by encoding the unit vectors
+func (rs *NTTReedSolomonCode) GetGeneratorMatrix() []uint32 {
+	generator := dataobjects.AlignedMake[uint32](uint64(rs.k * (rs.n - rs.k)))
+	unit := make([]uint32, rs.k)
+	for col := uint32(0); col < rs.k; col++ {
+		unit[col] = 1
+		codeword := rs.Encode(unit)
+		for row := uint32(0); row < rs.n-rs.k; row++ {
+			generator[row*rs.k+col] = codeword[rs.k+row]
+		}
+		unit[col] = 0
+	}
+	return generator
+}
+
+// Encode returns the codeword of message, the message polynomial is recovered with an inverse NTT on the
+// subgroup of order sub and evaluated on the whole domain with a forward NTT
+func (rs *NTTReedSolomonCode) Encode(message []uint32) []uint32 {
+	poly := make([]uint32, rs.size)
+	for i := uint32(0); i < rs.k; i++ {
+		poly[bitReverse(i, rs.sub)] = message[i]
+	}
+	rs.inverseNTT(poly, rs.sub)
+	rs.forwardNTT(poly, rs.size)
+
+	codeword := make([]uint32, rs.n)
+	copy(codeword, message[:rs.k])
+	for i := rs.k; i < rs.n; i++ {
+		codeword[i] = poly[bitReverse(rs.domainIndex(i), rs.size)]
+	}
+	return codeword
+}
+
+// Decode recovers the erased symbols by fast interpolation. With Z the vanishing polynomial of the unknown
+// domain points, f * Z is known on the whole domain and has degree < size, so one inverse NTT gives its
+// coefficients; f is then obtained by dividing by Z pointwise on a coset of the domain, where Z has no roots.
+func (rs *NTTReedSolomonCode) Decode(code []uint32, noisyQuery []bool) ([]uint32, error) {
+	if noisyQuery == nil || isAllFalse(noisyQuery[:rs.k]) {
+		return code[:rs.k], nil
+	}
+
+	field := rs.field
+	known := make([]bool, rs.size)
+	values := make([]uint32, rs.size)
+	for d := rs.k; d < rs.sub; d++ {
+		known[bitReverse(d, rs.size)] = true
+	}
+	received := uint32(0)
+	for i := uint32(0); i < rs.n; i++ {
+		if !noisyQuery[i] {
+			e := bitReverse(rs.domainIndex(i), rs.size)
+			known[e] = true
+			values[e] = code[i]
+			received++
+		}
+	}
+	if received < rs.k {
+		return []uint32{}, errors.New("Decoding Failed Due To Not Enough Data.")
+	}
+
+	// Points are indexed by their exponent e, w^e, from here on
+	unknown := []uint32{}
+	for e := uint32(0); e < rs.size; e++ {
+		if !known[e] {
+			unknown = append(unknown, fieldPow(field, rs.root, e))
+		}
+	}
+	z := make([]uint32, rs.size)
+	copy(z, rs.vanishing(unknown))
+
+	zEval := append([]uint32{}, z...)
+	rs.forwardNTT(zEval, rs.size)
+	for e := range values {
+		values[e] = field.Mul(values[e], zEval[e])
+	}
+	rs.inverseNTT(values, rs.size)
+
+	// Divide f * Z by Z on the coset shift * <root>
+	rs.scale(values, rs.shift)
+	rs.scale(z, rs.shift)
+	rs.forwardNTT(values, rs.size)
+	rs.forwardNTT(z, rs.size)
+	for e := range values {
+		values[e] = field.Mul(values[e], field.Inv(z[e]))
+	}
+	rs.inverseNTT(values, rs.size)
+	rs.scale(values, field.Inv(rs.shift))
+
+	// Evaluate f on the subgroup holding the message
+	f := values[:rs.sub]
+	rs.forwardNTT(f, rs.sub)
+	for i := uint32(0); i < rs.k; i++ {
+		if noisyQuery[i] {
+			code[i] = f[bitReverse(i, rs.sub)]
+		}
+	}
+	return code[:rs.k], nil
+}
+
+// DecodeBatch derives the decoding matrix by decoding the unit vectors on the first k symbols that arrived,
+// then applies it to all codewords
+func (rs *NTTReedSolomonCode) DecodeBatch(codes [][]uint32, erasures []bool) ([][]uint32, error) {
+	if isAllFalse(erasures[:rs.k]) {
+		return messages(codes, rs.k), nil
+	}
+	nodes, erased, err := decodingNodes(erasures, rs.k)
+	if err != nil {
+		return nil, err
+	}
+
+	noisy := make([]bool, rs.n)
+	for i := range noisy {
+		noisy[i] = true
+	}
+	for _, i := range nodes {
+		noisy[i] = false
+	}
+
+	decoding := dataobjects.NewMatrix(uint32(len(erased)), rs.k)
+	for j, node := range nodes {
+		unit := make([]uint32, rs.n)
+		unit[node] = 1
+		message, err := rs.Decode(unit, noisy)
+		if err != nil {
+			return nil, err
+		}
+		for r, i := range erased {
+			decoding.Set(uint32(r), uint32(j), message[i])
+		}
+	}
+
+	applyDecodingMatrix(rs.field, decoding, nodes, erased, codes)
+	return messages(codes, rs.k), nil
+}
+
+// vanishing returns prod_i (x - points_i) with a product tree, multiplying the halves with NTTs
+func (rs *NTTReedSolomonCode) vanishing(points []uint32) []uint32 {
+	if len(points) <= 32 {
+		return vanishingPoly(rs.field, points)
+	}
+	a := rs.vanishing(points[:len(points)/2])
+	b := rs.vanishing(points[len(points)/2:])
+
+	// The product has degree len(points) < size, so its length fits a transform the field supports
+	n := nextPowerOfTwo(uint32(len(a) + len(b) - 1))
+	fa := make([]uint32, n)
+	fb := make([]uint32, n)
+	copy(fa, a)
+	copy(fb, b)
+	rs.forwardNTT(fa, n)
+	rs.forwardNTT(fb, n)
+	for i := range fa {
+		fa[i] = rs.field.Mul(fa[i], fb[i])
+	}
+	rs.inverseNTT(fa, n)
+	return fa[:len(a)+len(b)-1]
+}
+
+// forwardNTT evaluates the polynomial a of degree < n on the subgroup of order n, a[e] = a(w_n^e)
+func (rs *NTTReedSolomonCode) forwardNTT(a []uint32, n uint32) {
+	tdm.NTT(a, n, fieldPow(rs.field, rs.root, rs.size/n), rs.q)
+}
+
+func (rs *NTTReedSolomonCode) inverseNTT(a []uint32, n uint32) {
+	tdm.NTT(a, n, rs.field.Inv(fieldPow(rs.field, rs.root, rs.size/n)), rs.q)
+	rs.field.MulVector(a, 0, a, 0, rs.field.Inv(n%rs.q), uint64(n))
+}
+
+// scale replaces a(x) by a(c x)
+func (rs *NTTReedSolomonCode) scale(a []uint32, c uint32) {
+	power := uint32(1)
+	for i := range a {
+		a[i] = rs.field.Mul(a[i], power)
+		power = rs.field.Mul(power, c)
+	}
+}
+
+func nextPowerOfTwo(n uint32) uint32 {
+	if n <= 1 {
+		return 1
+	}
+	return 1 << bits.Len32(n-1)
+}
+
+// bitReverse reverses the low log2(n) bits of i
+func bitReverse(i, n uint32) uint32 {
+	if n <= 1 {
+		return 0
+	}
+	return bits.Reverse32(i) >> (32 - bits.TrailingZeros32(n))
+}
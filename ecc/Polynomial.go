@@ -0,0 +1,110 @@
+package ecc
+
+import "RandomLinearCodePIR/dataobjects"
+
+// Dense polynomials over a prime field, coefficient i belongs to x^i and the zero polynomial is empty.
+// They are only used by the decoders, so the O(n^2) schoolbook algorithms are enough.
+
+func polyTrim(a []uint32) []uint32 {
+	for len(a) > 0 && a[len(a)-1] == 0 {
+		a = a[:len(a)-1]
+	}
+	return a
+}
+
+// polyDegree returns -1 for the zero polynomial
+func polyDegree(a []uint32) int {
+	return len(polyTrim(a)) - 1
+}
+
+func polyEval(field dataobjects.Field, a []uint32, x uint32) uint32 {
+	r := uint32(0)
+	for i := len(a) - 1; i >= 0; i-- {
+		r = field.Add(field.Mul(r, x), a[i])
+	}
+	return r
+}
+
+func polySub(field dataobjects.Field, a, b []uint32) []uint32 {
+	r := make([]uint32, max(len(a), len(b)))
+	copy(r, a)
+	for i := range b {
+		r[i] = field.Sub(r[i], b[i])
+	}
+	return polyTrim(r)
+}
+
+func polyMul(field dataobjects.Field, a, b []uint32) []uint32 {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	r := make([]uint32, len(a)+len(b)-1)
+	for i := range a {
+		for j := range b {
+			r[i+j] = field.Add(r[i+j], field.Mul(a[i], b[j]))
+		}
+	}
+	return polyTrim(r)
+}
+
+// polyDivMod returns q, r with a = q * b + r and deg r < deg b
+func polyDivMod(field dataobjects.Field, a, b []uint32) ([]uint32, []uint32) {
+	b = polyTrim(b)
+	if len(b) == 0 {
+		panic("polynomial division by zero")
+	}
+	r := append([]uint32{}, polyTrim(a)...)
+	if len(r) < len(b) {
+		return nil, r
+	}
+
+	q := make([]uint32, len(r)-len(b)+1)
+	lead := field.Inv(b[len(b)-1])
+	for i := len(q) - 1; i >= 0; i-- {
+		c := field.Mul(r[i+len(b)-1], lead)
+		q[i] = c
+		if c == 0 {
+			continue
+		}
+		for j := range b {
+			r[i+j] = field.Sub(r[i+j], field.Mul(c, b[j]))
+		}
+	}
+	return polyTrim(q), polyTrim(r[:len(b)-1])
+}
+
+// vanishingPoly returns prod_i (x - points_i)
+func vanishingPoly(field dataobjects.Field, points []uint32) []uint32 {
+	r := []uint32{1}
+	for _, a := range points {
+		r = polyMul(field, r, []uint32{field.Neg(a), 1})
+	}
+	return r
+}
+
+// interpolate returns the polynomial of degree < len(points) through (points_i, values_i), the points
+// must be distinct
+func interpolate(field dataobjects.Field, points, values []uint32) []uint32 {
+	g := vanishingPoly(field, points)
+	r := make([]uint32, len(points))
+	for i, a := range points {
+		// g / (x - a_i) is the Lagrange basis up to the factor prod_{j != i} (a_i - a_j)
+		basis, _ := polyDivMod(field, g, []uint32{field.Neg(a), 1})
+		c := field.Mul(values[i], field.Inv(polyEval(field, basis, a)))
+		for j := range basis {
+			r[j] = field.Add(r[j], field.Mul(c, basis[j]))
+		}
+	}
+	return polyTrim(r)
+}
+
+func fieldPow(field dataobjects.Field, base, exp uint32) uint32 {
+	r := uint32(1)
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			r = field.Mul(r, base)
+		}
+		base = field.Mul(base, base)
+	}
+	return r
+}
@@ -0,0 +1,126 @@
+//go:build purego || !cgo
+
+package ecc
+
+// Go implementations of the kernels in ReedSolomon.cpp, used when building with the purego tag or
+// CGO_ENABLED=0.
+
+// Build an n x m systematic RS generator over F_q at the evaluation points alphas, row-major with the
+// identity on top. The first m alphas must be pairwise distinct mod q.
+func GenerateSystematicRSMatrix(ECCLength, M_1, p uint32, alphas, output []uint32) {
+	n, m, q := ECCLength, M_1, p
+	if q < 2 || m < 1 || m > n {
+		panic("Reed-Solomon generator needs q >= 2 and 1 <= m <= n")
+	}
+
+	w := barycentricWeightsModQ(alphas[:m], q)
+
+	for row := uint32(0); row < m; row++ {
+		for col := uint32(0); col < m; col++ {
+			output[row*m+col] = 0
+		}
+		output[row*m+row] = 1
+	}
+
+	for row := m; row < n; row++ {
+		xstar := alphas[row] % q
+
+		// if xstar equals one of the first m nodes, row becomes that basis vector
+		matched := false
+		for j := uint32(0); j < m; j++ {
+			if xstar == alphas[j]%q {
+				for col := uint32(0); col < m; col++ {
+					output[row*m+col] = 0
+				}
+				output[row*m+j] = 1
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		for col := uint32(0); col < m; col++ {
+			output[row*m+col] = lagrangeBasisEvalModQ(col, alphas[:m], w, xstar, q)
+		}
+	}
+}
+
+// Evaluate the Lagrange interpolant through the k nodes (x, y) at index mod q
+func LagrangeInterpEval(x, y []uint32, k, index uint32, q uint32) uint32 {
+	if q < 2 || k < 1 {
+		panic("Lagrange interpolation needs q >= 2 and k >= 1")
+	}
+
+	w := barycentricWeightsModQ(x[:k], q)
+
+	xstar := index % q
+	for i := uint32(0); i < k; i++ {
+		if xstar == x[i]%q {
+			return y[i] % q
+		}
+	}
+
+	acc := uint32(0)
+	for i := uint32(0); i < k; i++ {
+		li := lagrangeBasisEvalModQ(i, x[:k], w, xstar, q)
+		acc = uint32((uint64(acc) + uint64(y[i]%q)*uint64(li)%uint64(q)) % uint64(q))
+	}
+	return acc
+}
+
+// w_i = 1 / prod_{j != i} (x_i - x_j) mod q
+func barycentricWeightsModQ(x []uint32, q uint32) []uint32 {
+	w := make([]uint32, len(x))
+	for i := range x {
+		den := uint64(1)
+		for j := range x {
+			if i == j {
+				continue
+			}
+			diff := modSubQ(x[i]%q, x[j]%q, q)
+			if diff == 0 {
+				panic("Duplicate nodes (mod q) or non-invertible denominator.")
+			}
+			den = den * uint64(diff) % uint64(q)
+		}
+		w[i] = modInvQ(uint32(den), q)
+	}
+	return w
+}
+
+// L_i(x*) = w_i * prod_{j != i} (x* - x_j) mod q
+func lagrangeBasisEvalModQ(i uint32, x, w []uint32, xstar, q uint32) uint32 {
+	num := uint64(1)
+	for j := range x {
+		if uint32(j) != i {
+			num = num * uint64(modSubQ(xstar%q, x[j]%q, q)) % uint64(q)
+		}
+	}
+	return uint32(uint64(w[i]) * num % uint64(q))
+}
+
+func modSubQ(a, b, q uint32) uint32 {
+	if a >= b {
+		return a - b
+	}
+	return uint32(uint64(a) + uint64(q) - uint64(b))
+}
+
+// Extended Euclid, q need not be below 2^31
+func modInvQ(a, q uint32) uint32 {
+	t0, t1 := int64(0), int64(1)
+	r0, r1 := int64(q), int64(a%q)
+	for r1 != 0 {
+		quot := r0 / r1
+		r0, r1 = r1, r0-quot*r1
+		t0, t1 = t1, t0-quot*t1
+	}
+
+	inv := t0 % int64(q)
+	if inv < 0 {
+		inv += int64(q)
+	}
+	return uint32(inv)
+}
@@ -0,0 +1,157 @@
+package ecc
+
+import (
+	"RandomLinearCodePIR/dataobjects"
+	"errors"
+	"math/rand"
+)
+
+// RandomLinearCode is a systematic linear code over F_q whose parity rows are sampled from a seed. Any k
+// received symbols whose generator rows are independent recover the message, which for a random code
+// happens with probability about 1 - 1/q once k symbols are in.
+type RandomLinearCode struct {
+	k     uint32
+	n     uint32
+	field *dataobjects.PrimeField
+	// n x k generator, identity on top
+	generator   *dataobjects.Matrix
+	minDistance uint32
+}
+
+// Up to this many k-subsets of rows are checked for invertibility to find out if the code is MDS
+const mdsCheckLimit = 1 << 12
+
+func NewRandomLinearCode(k, n, q uint32, seed int64) *RandomLinearCode {
+	if k == 0 || k > n {
+		panic("linear code needs 1 <= k <= n")
+	}
+
+	field := dataobjects.NewPrimeField(q)
+	rng := rand.New(rand.NewSource(seed))
+	generator := dataobjects.NewMatrix(n, k)
+	for i := uint32(0); i < k; i++ {
+		generator.Set(i, i, 1)
+	}
+	for i := k; i < n; i++ {
+		for j := uint32(0); j < k; j++ {
+			generator.Set(i, j, field.SampleElementWithSeed(rng))
+		}
+	}
+
+	rc := &RandomLinearCode{
+		k:         k,
+		n:         n,
+		field:     field,
+		generator: generator,
+	}
+	rc.minDistance = rc.distanceBound()
+	return rc
+}
+
+// distanceBound returns n - k + 1 if every k rows of the generator are independent, which makes the code
+// MDS, and the trivial bound 1 if they are not or there are too many subsets to check
+func (rc *RandomLinearCode) distanceBound() uint32 {
+	subsets := uint64(1)
+	for i := uint64(0); i < uint64(rc.k); i++ {
+		subsets = subsets * (uint64(rc.n) - i) / (i + 1)
+		if subsets > mdsCheckLimit {
+			return 1
+		}
+	}
+
+	rows := make([]uint32, rc.k)
+	for i := range rows {
+		rows[i] = uint32(i)
+	}
+	square := dataobjects.NewMatrix(rc.k, rc.k)
+	for {
+		for j, r := range rows {
+			copy(square.Row(uint32(j)), rc.generator.Row(r))
+		}
+		if dataobjects.Rank(rc.field, square) < rc.k {
+			return 1
+		}
+
+		// Next k-subset in lexicographic order
+		i := int(rc.k) - 1
+		for i >= 0 && rows[i] == rc.n-rc.k+uint32(i) {
+			i--
+		}
+		if i < 0 {
+			return rc.n - rc.k + 1
+		}
+		rows[i]++
+		for j := i + 1; j < int(rc.k); j++ {
+			rows[j] = rows[j-1] + 1
+		}
+	}
+}
+
+func (rc *RandomLinearCode) K() uint32                { return rc.k }
+func (rc *RandomLinearCode) N() uint32                { return rc.n }
+func (rc *RandomLinearCode) Field() dataobjects.Field { return rc.field }
+func (rc *RandomLinearCode) MinDistance() uint32      { return rc.minDistance }
+
+// Only return the evaluation part
+func (rc *RandomLinearCode) GetGeneratorMatrix() []uint32 {
+	return rc.generator.Data[rc.k*rc.k : rc.n*rc.k]
+}
+
+func (rc *RandomLinearCode) Encode(message []uint32) []uint32 {
+	return encodeWithGenerator(rc.field, rc.GetGeneratorMatrix(), message, rc.k, rc.n)
+}
+
+func (rc *RandomLinearCode) Decode(code []uint32, noisyQuery []bool) ([]uint32, error) {
+	messages, err := rc.DecodeBatch([][]uint32{code}, noisyQuery)
+	if err != nil {
+		return []uint32{}, err
+	}
+	return messages[0], nil
+}
+
+// DecodeBatch picks k received symbols with independent generator rows and inverts that k x k block, the
+// rows of the inverse belonging to erased message symbols form the decoding matrix
+func (rc *RandomLinearCode) DecodeBatch(codes [][]uint32, erasures []bool) ([][]uint32, error) {
+	if isAllFalse(erasures[:rc.k]) {
+		return messages(codes, rc.k), nil
+	}
+
+	received := []uint32{}
+	for i := uint32(0); i < rc.n; i++ {
+		if !erasures[i] {
+			received = append(received, i)
+		}
+	}
+	rows := dataobjects.NewMatrix(uint32(len(received)), rc.k)
+	for r, i := range received {
+		copy(rows.Row(uint32(r)), rc.generator.Row(i))
+	}
+
+	// The pivot columns of the transpose are independent rows
+	_, pivots := dataobjects.RowReduce(rc.field, dataobjects.Transpose(rows))
+	if uint32(len(pivots)) < rc.k {
+		return nil, errors.New("Decoding Failed Due To Not Enough Data.")
+	}
+
+	nodes := make([]uint32, rc.k)
+	square := dataobjects.NewMatrix(rc.k, rc.k)
+	for j, r := range pivots {
+		nodes[j] = received[r]
+		copy(square.Row(uint32(j)), rows.Row(r))
+	}
+	inverse, _ := dataobjects.Inverse(rc.field, square)
+
+	erased := []uint32{}
+	for i := uint32(0); i < rc.k; i++ {
+		if erasures[i] {
+			erased = append(erased, i)
+		}
+	}
+	decoding := dataobjects.NewMatrix(uint32(len(erased)), rc.k)
+	for r, i := range erased {
+		copy(decoding.Row(uint32(r)), inverse.Row(i))
+	}
+
+	applyDecodingMatrix(rc.field, decoding, nodes, erased, codes)
+	return messages(codes, rc.k), nil
+}
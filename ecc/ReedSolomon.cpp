@@ -1,3 +1,5 @@
+//go:build cgo && !purego
+
 #include <cstdint>
 #include <vector>
 #include <cassert>
@@ -6,26 +6,57 @@ import (
 )
 
 type ReedSolomonCode struct {
-	k uint32
-	n uint32
-	q uint32
+	k     uint32
+	n     uint32
+	q     uint32
+	field *dataobjects.PrimeField
+	// Symbol i is the evaluation at alphas[i]
+	alphas []uint32
+	// Evaluation part of the systematic generator
+	generator []uint32
 }
 
+// NewReedSolomonCode evaluates at the points 0, ..., n-1
 func NewReedSolomonCode(k, n, q uint32) *ReedSolomonCode {
+	return NewReedSolomonCodeWithPoints(k, n, q, getAlphas(n))
+}
+
+// NewReedSolomonCodeWithPoints evaluates at the given points, which must be pairwise distinct mod q
+func NewReedSolomonCodeWithPoints(k, n, q uint32, alphas []uint32) *ReedSolomonCode {
+	if k == 0 || k > n {
+		panic("Reed-Solomon code needs 1 <= k <= n")
+	}
+	if err := CheckPoints(q, alphas[:n]); err != nil {
+		panic(err.Error())
+	}
+	rsGeneratorMatrix := dataobjects.AlignedMake[uint32](uint64(k * n))
+	GenerateSystematicRSMatrix(n, k, q, alphas, rsGeneratorMatrix)
+
 	return &ReedSolomonCode{
-		k: k,
-		n: n,
-		q: q,
+		k:         k,
+		n:         n,
+		q:         q,
+		field:     dataobjects.NewPrimeField(q),
+		alphas:    alphas[:n],
+		generator: rsGeneratorMatrix[k*k:],
 	}
 }
 
+// Points returns the evaluation points of the symbols
+func (rs *ReedSolomonCode) Points() []uint32 { return rs.alphas }
+
+func (rs *ReedSolomonCode) K() uint32                { return rs.k }
+func (rs *ReedSolomonCode) N() uint32                { return rs.n }
+func (rs *ReedSolomonCode) Field() dataobjects.Field { return rs.field }
+func (rs *ReedSolomonCode) MinDistance() uint32      { return rs.n - rs.k + 1 }
+
 // Only return the evaluation part
-func (rs *ReedSolomonCode) GetGeneratorMatrix(M_1, ECCLength, p uint32) []uint32 {
-	alphas := getAlphas(ECCLength)
-	rsGeneratorMatrix := dataobjects.AlignedMake[uint32](uint64(M_1 * ECCLength))
+func (rs *ReedSolomonCode) GetGeneratorMatrix() []uint32 {
+	return rs.generator
+}
 
-	GenerateSystematicRSMatrix(ECCLength, M_1, p, alphas, rsGeneratorMatrix)
-	return rsGeneratorMatrix[M_1*M_1:]
+func (rs *ReedSolomonCode) Encode(message []uint32) []uint32 {
+	return encodeWithGenerator(rs.field, rs.generator, message, rs.k, rs.n)
 }
 
 func getAlphas(ECCLength uint32) []uint32 {
@@ -45,7 +76,7 @@ func (rs *ReedSolomonCode) Decode(code []uint32, noisyQuery []bool) ([]uint32, e
 		idx := uint32(0)
 		for i := range noisyQuery {
 			if !noisyQuery[i] && idx < rs.k {
-				x_in[idx] = uint32(i)
+				x_in[idx] = rs.alphas[i]
 				y_in[idx] = code[i]
 				idx += 1
 			}
@@ -58,7 +89,7 @@ func (rs *ReedSolomonCode) Decode(code []uint32, noisyQuery []bool) ([]uint32, e
 		// TODO: Replace it with ReedSolomon Decoder
 		for i := uint32(0); i < rs.k; i++ {
 			if noisyQuery[i] {
-				code[i] = LagrangeInterpEval(x_in, y_in, rs.k, i, rs.q)
+				code[i] = LagrangeInterpEval(x_in, y_in, rs.k, rs.alphas[i], rs.q)
 			}
 		}
 
@@ -66,6 +97,30 @@ func (rs *ReedSolomonCode) Decode(code []uint32, noisyQuery []bool) ([]uint32, e
 	}
 }
 
+// DecodeBatch interpolates the erased message symbols from the first k symbols that arrived, the Lagrange
+// basis at the erased points is evaluated once and applied to all codewords
+func (rs *ReedSolomonCode) DecodeBatch(codes [][]uint32, erasures []bool) ([][]uint32, error) {
+	if isAllFalse(erasures[:rs.k]) {
+		return messages(codes, rs.k), nil
+	}
+	nodes, erased, err := decodingNodes(erasures, rs.k)
+	if err != nil {
+		return nil, err
+	}
+
+	// The generator on the points of nodes followed by those of erased has L_j(erased_r) in row k + r
+	points := make([]uint32, 0, len(nodes)+len(erased))
+	for _, i := range append(append([]uint32{}, nodes...), erased...) {
+		points = append(points, rs.alphas[i])
+	}
+	generator := dataobjects.AlignedMake[uint32](uint64(len(points)) * uint64(rs.k))
+	GenerateSystematicRSMatrix(uint32(len(points)), rs.k, rs.q, points, generator)
+	decoding := &dataobjects.Matrix{Rows: uint32(len(erased)), Cols: rs.k, Data: generator[rs.k*rs.k:]}
+
+	applyDecodingMatrix(rs.field, decoding, nodes, erased, codes)
+	return messages(codes, rs.k), nil
+}
+
 func isAllFalse(vec []bool) bool {
 	for i := range vec {
 		if vec[i] {
@@ -0,0 +1,338 @@
+package ecc
+
+import (
+	"RandomLinearCodePIR/dataobjects"
+	"math"
+	"math/bits"
+	"math/rand"
+	"testing"
+)
+
+// Test erasure decoding of a Reed-Solomon code over GF(17^2) whose length exceeds the characteristic
+func TestExtensionReedSolomonCode(t *testing.T) {
+	field := dataobjects.NewExtensionField(17, 2)
+	k, n := uint32(8), uint32(40)
+	d := field.Degree()
+
+	code := GetECCCode(ECCConfig{Name: ExtensionReedSolomon, Q: 17, N: n, K: k, Degree: d})
+	generator := code.GetGeneratorMatrix()
+
+	message := make([]uint32, k*d)
+	for i := range message {
+		message[i] = uint32(rand.Intn(17))
+	}
+
+	codeword := make([]uint32, n*d)
+	copy(codeword, message)
+	term := field.NewElement()
+	for row := uint32(0); row < n-k; row++ {
+		symbol := codeword[(k+row)*d : (k+row+1)*d]
+		for col := uint32(0); col < k; col++ {
+			entry := generator[(row*k+col)*d : (row*k+col+1)*d]
+			field.MulElement(term, entry, message[col*d:(col+1)*d])
+			field.AddElement(symbol, symbol, term)
+		}
+	}
+
+	noisy := make([]bool, n)
+	for _, i := range rand.Perm(int(n))[:n-k] {
+		noisy[i] = true
+		codeword[uint32(i)*d] = uint32(rand.Intn(17))
+	}
+
+	batch, err := code.DecodeBatch([][]uint32{append([]uint32{}, codeword...)}, noisy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := code.Decode(codeword, noisy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range message {
+		if decoded[i] != message[i] || batch[0][i] != message[i] {
+			t.Fatalf("decoded message differs at limb %d", i)
+		}
+	}
+
+	// GF(65537^2) does not pack into a uint32, the code must still work through the limb API
+	large := GetECCCode(ECCConfig{Name: ExtensionReedSolomon, Q: 65537, N: 20, K: 4, Degree: 2}).(*ExtensionReedSolomonCode)
+	if large.ExtensionField().Packable() || large.ExtensionField().Degree() != 2 {
+		t.Fatalf("wrong extension field of the code over GF(65537^2)")
+	}
+	if p := CodeFailureProbability(large, 0.01, 16); p <= 0 || p >= 1 {
+		t.Fatalf("failure probability %g of the code over GF(65537^2)", p)
+	}
+}
+
+// Test errors-and-erasures decoding: e erasures and (n - k - e) / 2 errors at unknown positions
+func TestGaoReedSolomonCode(t *testing.T) {
+	q := uint32(65537)
+	field := dataobjects.NewPrimeField(q)
+	k, n, e := uint32(8), uint32(24), uint32(4)
+
+	code := GetECCCode(ECCConfig{Name: ReedSolomonGao, Q: q, N: n, K: k})
+	generator := code.GetGeneratorMatrix()
+
+	message := field.SampleVector(k)
+	codeword := make([]uint32, n)
+	copy(codeword, message)
+	for row := uint32(0); row < n-k; row++ {
+		for col := uint32(0); col < k; col++ {
+			codeword[k+row] = field.Add(codeword[k+row], field.Mul(generator[row*k+col], message[col]))
+		}
+	}
+
+	noisy := make([]bool, n)
+	positions := rand.Perm(int(n))
+	for _, i := range positions[:e] {
+		noisy[i] = true
+		codeword[i] = uint32(rand.Intn(int(q)))
+	}
+	for _, i := range positions[e : e+(n-k-e)/2] {
+		codeword[i] = field.Add(codeword[i], 1+uint32(rand.Intn(int(q-1))))
+	}
+
+	decoded, err := code.Decode(codeword, noisy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range message {
+		if decoded[i] != message[i] {
+			t.Fatalf("decoded message differs at %d", i)
+		}
+	}
+}
+
+// Test the NTT encoder against the generator matrix and erasure decoding with a message length that is
+// not a power of two
+func TestNTTReedSolomonCode(t *testing.T) {
+	q := uint32(65537)
+	field := dataobjects.NewPrimeField(q)
+	k, n := uint32(37), uint32(150)
+
+	code := GetECCCode(ECCConfig{Name: NTTReedSolomon, Q: q, N: n, K: k})
+	generator := code.GetGeneratorMatrix()
+
+	message := field.SampleVector(k)
+	codeword := code.Encode(message)
+	for row := uint32(0); row < n-k; row++ {
+		sum := uint32(0)
+		for col := uint32(0); col < k; col++ {
+			sum = field.Add(sum, field.Mul(generator[row*k+col], message[col]))
+		}
+		if codeword[k+row] != sum {
+			t.Fatalf("parity symbol %d differs from the generator matrix", row)
+		}
+	}
+
+	noisy := make([]bool, n)
+	for _, i := range rand.Perm(int(n))[:n-k] {
+		noisy[i] = true
+		codeword[i] = uint32(rand.Intn(int(q)))
+	}
+
+	decoded, err := code.Decode(codeword, noisy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range message {
+		if decoded[i] != message[i] {
+			t.Fatalf("decoded message differs at %d", i)
+		}
+	}
+}
+
+// Test that DecodeBatch agrees with decoding the codewords one by one
+func TestDecodeBatch(t *testing.T) {
+	q := uint32(65537)
+	field := dataobjects.NewPrimeField(q)
+	k, n := uint32(6), uint32(20)
+
+	for _, name := range []string{ReedSolomon, ReedSolomonGao, NTTReedSolomon, RandomLinear, LDPC} {
+		code := GetECCCode(ECCConfig{Name: name, Q: q, N: n, K: k})
+		generator := code.GetGeneratorMatrix()
+
+		erasures := make([]bool, n)
+		for _, i := range []int{0, 2, 5, 9, 13} {
+			erasures[i] = true
+		}
+
+		batch := make([][]uint32, 8)
+		single := make([][]uint32, len(batch))
+		for c := range batch {
+			codeword := make([]uint32, n)
+			copy(codeword, field.SampleVector(k))
+			for row := uint32(0); row < n-k; row++ {
+				for col := uint32(0); col < k; col++ {
+					codeword[k+row] = field.Add(codeword[k+row], field.Mul(generator[row*k+col], codeword[col]))
+				}
+			}
+			for i := range erasures {
+				if erasures[i] {
+					codeword[i] = field.SampleElement()
+				}
+			}
+			batch[c] = codeword
+			single[c] = append([]uint32{}, codeword...)
+		}
+
+		decoded, err := code.DecodeBatch(batch, erasures)
+		if err != nil {
+			t.Fatal(err)
+		}
+		codeword := code.Encode(decoded[0])
+		for i := k; i < n; i++ {
+			if !erasures[i] && codeword[i] != batch[0][i] {
+				t.Fatalf("%s: encoder differs from the generator matrix at %d", name, i)
+			}
+		}
+		for c := range single {
+			expected, err := code.Decode(single[c], erasures)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range expected {
+				if decoded[c][i] != expected[i] {
+					t.Fatalf("%s: codeword %d differs at %d", name, c, i)
+				}
+			}
+		}
+	}
+}
+
+// Test that peeling reports a stopping set: an erased message symbol whose checks were all erased as well
+func TestLDPCStoppingSet(t *testing.T) {
+	q := uint32(65537)
+	k, n := uint32(6), uint32(20)
+	code := NewLDPCCode(k, n, q, 1)
+
+	erasures := make([]bool, n)
+	erasures[0] = true
+	for c, check := range code.checks {
+		if contains(check, 0) {
+			erasures[k+uint32(c)] = true
+		}
+	}
+
+	codeword := code.Encode(dataobjects.NewPrimeField(q).SampleVector(k))
+	_, err := code.DecodeBatch([][]uint32{codeword}, erasures)
+	if err == nil || err.Error() != "Decoding Failed Due To Stopping Set." {
+		t.Fatalf("expected the stopping set error, got %v", err)
+	}
+}
+
+// Test config errors and codes registered from outside the built-in set
+func TestECCRegistry(t *testing.T) {
+	for _, config := range []ECCConfig{
+		{Name: "NoSuchCode", Q: 65537, N: 7, K: 4},
+		{Name: ReedSolomon, Q: 65537, N: 4, K: 7},
+		{Name: ReedSolomon, Q: 13, N: 20, K: 4},
+		{Name: NTTReedSolomon, Q: 13, N: 12, K: 4},
+	} {
+		if _, err := NewECCCode(config); err == nil {
+			t.Fatalf("config %+v was accepted", config)
+		}
+	}
+
+	RegisterECC("TestRepetition", func(c ECCConfig) (ErasureCorrectionCode, error) {
+		return NewReedSolomonCode(1, c.N, c.Q), nil
+	})
+	code, err := NewECCCode(ECCConfig{Name: "TestRepetition", Q: 65537, N: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code.K() != 1 || code.N() != 5 || code.MinDistance() != 5 {
+		t.Fatalf("unexpected parameters k = %d, n = %d, d = %d", code.K(), code.N(), code.MinDistance())
+	}
+	codeword := code.Encode([]uint32{42})
+	for i := range codeword {
+		if codeword[i] != 42 {
+			t.Fatalf("repetition codeword is %v", codeword)
+		}
+	}
+}
+
+// Test Reed-Solomon codes on a subgroup and on a coset, and rejection of repeated points
+func TestEvaluationPoints(t *testing.T) {
+	q := uint32(65537)
+	field := dataobjects.NewPrimeField(q)
+	k, n := uint32(5), uint32(16)
+
+	subgroup, err := SubgroupPoints(q, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coset, err := CosetPoints(q, n, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{ReedSolomon, ReedSolomonGao} {
+		for _, points := range [][]uint32{subgroup, coset} {
+			code := GetECCCode(ECCConfig{Name: name, Q: q, N: n, K: k, Points: points})
+
+			// The codeword is the evaluation of the interpolant of the message on the first k points
+			message := field.SampleVector(k)
+			codeword := code.Encode(message)
+			for i := k; i < n; i++ {
+				if codeword[i] != LagrangeInterpEval(points, message, k, points[i], q) {
+					t.Fatalf("%s: symbol %d is not the evaluation at point %d", name, i, points[i])
+				}
+			}
+
+			noisy := make([]bool, n)
+			for _, i := range []int{0, 1, 4, 8, 15} {
+				noisy[i] = true
+				codeword[i] = 0
+			}
+			decoded, err := code.Decode(codeword, noisy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range message {
+				if decoded[i] != message[i] {
+					t.Fatalf("%s: decoded message differs at %d", name, i)
+				}
+			}
+		}
+	}
+
+	repeated := append([]uint32{}, subgroup...)
+	repeated[7] = repeated[2]
+	if _, err := NewECCCode(ECCConfig{Name: ReedSolomon, Q: q, N: n, K: k, Points: repeated}); err == nil {
+		t.Fatal("repeated evaluation points were accepted")
+	}
+}
+
+// Test the failure probability against enumerating all noise patterns, and the suggested length
+func TestFailureProbability(t *testing.T) {
+	epsi, l := 0.002, uint32(100)
+	p := SliceNoiseProbability(epsi, l)
+	if math.Abs(p-(1-math.Pow(1-epsi, float64(l)))) > 1e-12 {
+		t.Fatalf("slice noise probability %g", p)
+	}
+
+	k, n := uint32(3), uint32(8)
+	expected := 0.0
+	for pattern := 0; pattern < 1<<n; pattern++ {
+		noisy := bits.OnesCount(uint(pattern))
+		if noisy > int(n-k) {
+			expected += math.Pow(p, float64(noisy)) * math.Pow(1-p, float64(int(n)-noisy))
+		}
+	}
+	if got := DecodingFailureProbability(epsi, l, k, n); math.Abs(got-expected) > 1e-9*expected {
+		t.Fatalf("failure probability %g, expected %g", got, expected)
+	}
+
+	target := 1e-30
+	length, err := SuggestECCLength(epsi, l, k, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if DecodingFailureProbability(epsi, l, k, length) > target || DecodingFailureProbability(epsi, l, k, length-1) <= target {
+		t.Fatalf("suggested length %d is not the smallest reaching %g", length, target)
+	}
+	if _, err := SuggestECCLength(1, l, k, target); err == nil {
+		t.Fatal("a length was suggested although every slice is noisy")
+	}
+}
@@ -0,0 +1,240 @@
+package linearcode
+
+import (
+	"RandomLinearCodePIR/dataobjects"
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+// A LinearCode of length N = L + K gives two codes:
+//   D = (I_L | P) spanned by the encoded database rows (x, P^T x), dimension L, built from the RLC matrix P^T
+//   C = (-P^T | I_K) spanned by the query codewords (-P c, c), dimension K, built from the dual matrix -P
+// and the scheme needs G_D * G_C^T = 0. The security of the query rests on the distance of C's dual D.
+
+type AnalysisOptions struct {
+	// Largest number of column subsets checked for an exact distance, information sets are sampled above it
+	ExactLimit uint64
+	// Information sets sampled for each estimate
+	Samples uint32
+	// Seed of the information-set sampling
+	Seed int64
+	// A distance more than Slack below the Singleton bound flags the code as degenerate
+	Slack uint32
+}
+
+func DefaultAnalysisOptions() AnalysisOptions {
+	return AnalysisOptions{
+		ExactLimit: 1 << 16,
+		Samples:    64,
+		Seed:       1,
+		Slack:      1,
+	}
+}
+
+type CodeAnalysis struct {
+	Config LinearCodeConfig
+	N      uint32
+	// G_D * G_C^T = 0
+	Dual bool
+	// Distances of D and C, exact when enumerated, otherwise the lowest weight seen over the sampled information sets
+	Distance          uint32
+	DistanceExact     bool
+	DualDistance      uint32
+	DualDistanceExact bool
+	Degenerate        bool
+	Reasons           []string
+}
+
+func (a *CodeAnalysis) String() string {
+	exact := map[bool]string{true: "exact", false: "sampled"}
+	s := fmt.Sprintf("%s K %d L %d seed %d: dual %t, distance %d/%d (%s), dual distance %d/%d (%s)",
+		a.Config.Name, a.Config.K, a.Config.L, a.Config.Seed, a.Dual,
+		a.Distance, a.Config.K+1, exact[a.DistanceExact], a.DualDistance, a.Config.L+1, exact[a.DualDistanceExact])
+	if a.Degenerate {
+		s += fmt.Sprintf(", DEGENERATE %v", a.Reasons)
+	}
+	return s
+}
+
+// AnalyzeLinearCode checks the duality of the code of config and estimates the distances of D and C
+func AnalyzeLinearCode(config LinearCodeConfig, options AnalysisOptions) (*CodeAnalysis, error) {
+	switch config.Name {
+	case Random, QuasiCyclic, StreamingRandom, Vandermonde:
+	case ExtensionVandermonde:
+		return nil, errors.New("analysis needs a code over the prime field, " + config.Name + " is not supported")
+	default:
+		return nil, errors.New("unsupported linear code: " + config.Name)
+	}
+	if config.K == 0 || config.L == 0 {
+		return nil, errors.New("K and L must be positive")
+	}
+
+	field := config.Field
+	K, L := config.K, config.L
+	code := GetLinearCode(config)
+	rlc := code.Generate1DRLCMatrix(L, K, field, config.Seed)
+	dual := code.Generate1DDualMatrix(L, K, field, config.Seed)
+
+	N := L + K
+	gD := dataobjects.NewMatrix(L, N)
+	for i := uint32(0); i < L; i++ {
+		gD.Set(i, i, 1)
+		for j := uint32(0); j < K; j++ {
+			gD.Set(i, L+j, rlc[j*L+i])
+		}
+	}
+	gC := dataobjects.NewMatrix(K, N)
+	for j := uint32(0); j < K; j++ {
+		for i := uint32(0); i < L; i++ {
+			gC.Set(j, i, dual[i*K+j])
+		}
+		gC.Set(j, L+j, 1)
+	}
+
+	analysis := &CodeAnalysis{Config: config, N: N, Dual: true}
+	product := dataobjects.MatMul(field, gD, dataobjects.Transpose(gC))
+	for _, v := range product.Data {
+		if v != 0 {
+			analysis.Dual = false
+			analysis.Reasons = append(analysis.Reasons, "G_D * G_C^T is not zero")
+			break
+		}
+	}
+
+	// The distance of a code is the least number of dependent columns of its dual's generator
+	analysis.Distance, analysis.DistanceExact = exactDistance(field, gC, options.ExactLimit)
+	analysis.DualDistance, analysis.DualDistanceExact = exactDistance(field, gD, options.ExactLimit)
+	if !analysis.DistanceExact || !analysis.DualDistanceExact {
+		// One reduction of the smaller generator gives codewords of both codes
+		rng := rand.New(rand.NewSource(options.Seed))
+		var dD, dC uint32
+		if K <= L {
+			dC, dD = sampleDistances(field, gC, options.Samples, rng)
+		} else {
+			dD, dC = sampleDistances(field, gD, options.Samples, rng)
+		}
+		if !analysis.DistanceExact {
+			analysis.Distance = dD
+		}
+		if !analysis.DualDistanceExact {
+			analysis.DualDistance = dC
+		}
+	}
+
+	if analysis.Distance+options.Slack < K+1 {
+		analysis.Reasons = append(analysis.Reasons, fmt.Sprintf("distance %d is far below the Singleton bound %d", analysis.Distance, K+1))
+	}
+	if analysis.DualDistance+options.Slack < L+1 {
+		analysis.Reasons = append(analysis.Reasons, fmt.Sprintf("dual distance %d is far below the Singleton bound %d", analysis.DualDistance, L+1))
+	}
+	analysis.Degenerate = len(analysis.Reasons) > 0
+	return analysis, nil
+}
+
+// exactDistance returns the least number of linearly dependent columns of h, the distance of the code
+// with parity-check matrix h, or false if more than limit column subsets would have to be checked
+func exactDistance(field dataobjects.Field, h *dataobjects.Matrix, limit uint64) (uint32, bool) {
+	n := h.Cols
+	checked := uint64(0)
+	// Any rank(h) + 1 columns are dependent
+	for t := uint32(1); t <= min(h.Rows, n-1)+1; t++ {
+		subsets := binomial(n, t)
+		if subsets > limit-min(checked, limit) {
+			return 0, false
+		}
+		checked += subsets
+
+		cols := make([]uint32, t)
+		for i := range cols {
+			cols[i] = uint32(i)
+		}
+		sub := dataobjects.NewMatrix(h.Rows, t)
+		for {
+			for r := uint32(0); r < h.Rows; r++ {
+				for c, j := range cols {
+					sub.Set(r, uint32(c), h.At(r, j))
+				}
+			}
+			if dataobjects.Rank(field, sub) < t {
+				return t, true
+			}
+
+			// Next t-subset in lexicographic order
+			i := int(t) - 1
+			for i >= 0 && cols[i] == n-t+uint32(i) {
+				i--
+			}
+			if i < 0 {
+				break
+			}
+			cols[i]++
+			for j := i + 1; j < int(t); j++ {
+				cols[j] = cols[j-1] + 1
+			}
+		}
+	}
+	return n, true
+}
+
+// sampleDistances returns the lowest weights seen in the code of g and in its dual over samples random
+// information sets. On an information set g reduces to the identity, its rows are codewords of g and each
+// other column c gives the dual codeword with 1 at c and the negated column on the information set.
+func sampleDistances(field dataobjects.Field, g *dataobjects.Matrix, samples uint32, rng *rand.Rand) (uint32, uint32) {
+	code, dual := g.Cols, g.Cols
+	permuted := dataobjects.NewMatrix(g.Rows, g.Cols)
+	for s := uint32(0); s < max(samples, 1); s++ {
+		// Weights do not depend on the order of the columns, so the permutation is not undone
+		perm := rng.Perm(int(g.Cols))
+		for i := uint32(0); i < g.Rows; i++ {
+			for j, p := range perm {
+				permuted.Set(i, uint32(j), g.At(i, uint32(p)))
+			}
+		}
+		reduced, pivots := dataobjects.RowReduce(field, permuted)
+
+		isPivot := make([]bool, g.Cols)
+		for _, c := range pivots {
+			isPivot[c] = true
+		}
+		for i := range pivots {
+			code = min(code, weight(reduced.Row(uint32(i))))
+		}
+		for c := uint32(0); c < g.Cols; c++ {
+			if isPivot[c] {
+				continue
+			}
+			w := uint32(1)
+			for i := range pivots {
+				if reduced.At(uint32(i), c) != 0 {
+					w++
+				}
+			}
+			dual = min(dual, w)
+		}
+	}
+	return code, dual
+}
+
+func weight(v []uint32) uint32 {
+	w := uint32(0)
+	for _, x := range v {
+		if x != 0 {
+			w++
+		}
+	}
+	return w
+}
+
+// binomial returns n choose t, saturating at the largest uint64
+func binomial(n, t uint32) uint64 {
+	result := uint64(1)
+	for i := uint64(0); i < uint64(t); i++ {
+		next := result * (uint64(n) - i)
+		if next/(uint64(n)-i) != result {
+			return ^uint64(0)
+		}
+		result = next / (i + 1)
+	}
+	return result
+}
@@ -17,7 +17,7 @@ type EvaluationCode struct {
 func NewEvaluationCode(K, L uint32, field dataobjects.Field) *EvaluationCode {
 	p := field.GetChar()
 	if K > p-1 || L > p-1 {
-		panic("Currently Only support for K < P to have enough evaluation points")
+		panic("Currently Only support for K < P to have enough evaluation points, use ExtensionVandermonde for larger K and L")
 	}
 	n := uint32(1) << uint32(math.Ceil(math.Log2(float64(max(L, K)))))
 	return &EvaluationCode{K: K, L: L,
@@ -27,32 +27,66 @@ func NewEvaluationCode(K, L uint32, field dataobjects.Field) *EvaluationCode {
 	}
 }
 
+// The code plays the role of the random code with P = -V, so the matrices below have the layouts of the
+// random-code functions and EncodeLSN(r) = Generate1DDualMatrix * r, EncodeDual(x) = Generate1DRLCMatrix * x.
+// The dimensions and the field are the code's own, the seed is not used.
+
+// Return V of D = (V // I) flattened, L x K
 func (ec *EvaluationCode) Generate1DDualMatrix(L, K uint32, field dataobjects.Field, seed int64) []uint32 {
-	return []uint32{}
+	ec.checkDimensions(L, K)
+	V := ec.GenerateV()
+
+	vmatrix := dataobjects.AlignedMake[uint32](uint64(K * L))
+	for i := uint32(0); i < L; i++ {
+		copy(vmatrix[i*K:(i+1)*K], V[i])
+	}
+	return vmatrix
 }
 
-// C = (V // L) where V is L x K, each row is an evalution point
+// Return -V^T of C = (I // -V^T) flattened, K x L
 func (ec *EvaluationCode) Generate1DRLCMatrix(L, K uint32, p dataobjects.Field, seed int64) []uint32 {
-	return []uint32{}
+	ec.checkDimensions(L, K)
+	V := ec.GenerateV()
+
+	vmatrix := dataobjects.AlignedMake[uint32](uint64(K * L))
+	for j := uint32(0); j < K; j++ {
+		for i := uint32(0); i < L; i++ {
+			vmatrix[j*L+i] = ec.Field.Neg(V[i][j])
+		}
+	}
+	return vmatrix
 }
 
+// GenerateV returns the L x K Vandermonde matrix V[i][j] = omega^(ij), row i evaluates at omega^i
 func (ec *EvaluationCode) GenerateV() [][]uint32 {
-	V := make([][]uint32, ec.L)
-	// k := uint32(math.Ceil(math.Log2(float64(max(ec.L,ec.K)))))
+	powers := make([]uint32, ec.n)
+	powers[0] = 1
+	for t := uint32(1); t < ec.n; t++ {
+		powers[t] = ec.Field.Mul(powers[t-1], ec.omega)
+	}
 
-	// omega := tdm.NthRootOfUnity(ec.q, k)
+	V := make([][]uint32, ec.L)
+	for i := uint32(0); i < ec.L; i++ {
+		V[i] = dataobjects.AlignedMake[uint32](uint64(ec.K))
+		for j := uint32(0); j < ec.K; j++ {
+			V[i][j] = powers[uint64(i)*uint64(j)%uint64(ec.n)]
+		}
+	}
 	return V
 }
 
-func (ec *EvaluationCode) encode(message []uint32) []uint32 {
-	l := len(message)
-	if l < int(ec.n) {
-		newMessage := dataobjects.AlignedMake[uint32](uint64(ec.n))
-		copy(newMessage, message)
-		message = newMessage
+func (ec *EvaluationCode) checkDimensions(L, K uint32) {
+	if L != ec.L || K != ec.K {
+		panic("Matrix dimensions differ from the code's L and K")
 	}
-	tdm.NTT(message, ec.n, ec.omega, ec.Field.GetChar())
-	return message
+}
+
+// encode returns the NTT of message padded to n, message itself is left untouched
+func (ec *EvaluationCode) encode(message []uint32) []uint32 {
+	encoded := dataobjects.AlignedMake[uint32](uint64(ec.n))
+	copy(encoded, message)
+	tdm.NTT(encoded, ec.n, ec.omega, ec.Field.GetChar())
+	return encoded
 }
 
 // Dual Code C = (I//-V) -V has dimension K x L
@@ -0,0 +1,97 @@
+package linearcode
+
+import (
+	"RandomLinearCodePIR/dataobjects"
+	"RandomLinearCodePIR/tdm"
+	"math"
+)
+
+// ExtensionEvaluationCode is EvaluationCode over GF(p^d). The NTT domain is bounded by the largest
+// power of two dividing p^d - 1 instead of p - 1, e.g. 2^17 for GF(65537^2). Messages and codewords
+// use the element-major limb layout of ExtensionField, so EncodeLSN takes K * d limbs and returns L * d.
+type ExtensionEvaluationCode struct {
+	K     uint32
+	L     uint32
+	Field *dataobjects.ExtensionField
+	n     uint32
+	omega []uint32
+}
+
+func NewExtensionEvaluationCode(K, L uint32, field *dataobjects.ExtensionField) *ExtensionEvaluationCode {
+	n := uint32(1) << uint32(math.Ceil(math.Log2(float64(max(L, K)))))
+	if uint64(n) > field.MaxPowerOfTwoRootOfUnity() {
+		panic("Not enough evaluation points, increase the extension degree")
+	}
+	return &ExtensionEvaluationCode{K: K, L: L,
+		Field: field,
+		n:     n,
+		omega: field.NthRootOfUnity(uint64(n)),
+	}
+}
+
+// Return V of D = (V // I) flattened, L x K with d limbs per entry, as for EvaluationCode
+func (ec *ExtensionEvaluationCode) Generate1DDualMatrix(L, K uint32, field dataobjects.Field, seed int64) []uint32 {
+	ec.checkDimensions(L, K)
+	d := ec.Field.Degree()
+	powers := ec.powers()
+
+	vmatrix := dataobjects.AlignedMake[uint32](uint64(K * L * d))
+	for i := uint32(0); i < L; i++ {
+		for j := uint32(0); j < K; j++ {
+			copy(vmatrix[(i*K+j)*d:], powers[uint64(i)*uint64(j)%uint64(ec.n)])
+		}
+	}
+	return vmatrix
+}
+
+// Return -V^T of C = (I // -V^T) flattened, K x L with d limbs per entry
+func (ec *ExtensionEvaluationCode) Generate1DRLCMatrix(L, K uint32, p dataobjects.Field, seed int64) []uint32 {
+	ec.checkDimensions(L, K)
+	d := ec.Field.Degree()
+	powers := ec.powers()
+
+	vmatrix := dataobjects.AlignedMake[uint32](uint64(K * L * d))
+	for j := uint32(0); j < K; j++ {
+		for i := uint32(0); i < L; i++ {
+			ec.Field.NegElement(vmatrix[(j*L+i)*d:(j*L+i+1)*d], powers[uint64(i)*uint64(j)%uint64(ec.n)])
+		}
+	}
+	return vmatrix
+}
+
+// powers returns omega^t for t < n
+func (ec *ExtensionEvaluationCode) powers() [][]uint32 {
+	powers := make([][]uint32, ec.n)
+	powers[0] = ec.Field.Embed(1)
+	for t := uint32(1); t < ec.n; t++ {
+		powers[t] = ec.Field.NewElement()
+		ec.Field.MulElement(powers[t], powers[t-1], ec.omega)
+	}
+	return powers
+}
+
+func (ec *ExtensionEvaluationCode) checkDimensions(L, K uint32) {
+	if L != ec.L || K != ec.K {
+		panic("Matrix dimensions differ from the code's L and K")
+	}
+}
+
+func (ec *ExtensionEvaluationCode) encode(message []uint32) []uint32 {
+	d := ec.Field.Degree()
+	padded := dataobjects.AlignedMake[uint32](uint64(ec.n * d))
+	copy(padded, message)
+	tdm.ExtensionNTT(ec.Field, padded, ec.n, ec.omega)
+	return padded
+}
+
+// Dual Code C = (I//-V) -V has dimension K x L
+func (ec *ExtensionEvaluationCode) EncodeDual(message []uint32) []uint32 {
+	encoded := ec.encode(message)[:ec.K*ec.Field.Degree()]
+	ec.Field.NegLimbVector(encoded, 0, uint64(ec.K))
+	return encoded
+}
+
+// Dual Code D = (V//I)
+func (ec *ExtensionEvaluationCode) EncodeLSN(message []uint32) []uint32 {
+	return ec.encode(message)[:ec.L*ec.Field.Degree()]
+}
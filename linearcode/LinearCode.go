@@ -3,8 +3,14 @@ package linearcode
 import "RandomLinearCodePIR/dataobjects"
 
 const (
-	RandomLinearCode = "Random"
-	Vandermonde      = "Fast"
+	Random               = "Random"
+	QuasiCyclic          = "QuasiCyclic"
+	StreamingRandom      = "StreamingRandom"
+	Vandermonde          = "Fast"
+	ExtensionVandermonde = "FastExtension"
+
+	// Deprecated: use Random
+	RandomLinearCode = Random
 )
 
 type LinearCodeConfig struct {
@@ -12,6 +18,10 @@ type LinearCodeConfig struct {
 	K     uint32
 	L     uint32
 	Field dataobjects.Field
+	// Extension degree d for codes over GF(p^d), p = Field.GetChar()
+	Degree uint32
+	// Seed of the random, quasi-cyclic and streaming codes' P
+	Seed int64
 }
 
 type LinearCode interface {
@@ -23,8 +33,16 @@ type LinearCode interface {
 
 func GetLinearCode(config LinearCodeConfig) LinearCode {
 	switch config.Name {
+	case Random:
+		return NewRandomCode(config.K, config.L, config.Field, config.Seed)
+	case QuasiCyclic:
+		return NewQuasiCyclicCode(config.K, config.L, config.Field, config.Seed)
+	case StreamingRandom:
+		return NewStreamingRandomCode(config.K, config.L, config.Field, config.Seed)
 	case Vandermonde:
 		return NewEvaluationCode(config.K, config.L, config.Field)
+	case ExtensionVandermonde:
+		return NewExtensionEvaluationCode(config.K, config.L, dataobjects.NewExtensionField(config.Field.GetChar(), config.Degree))
 	default:
 		panic("Unsupported Linear Code: " + config.Name)
 	}
@@ -0,0 +1,53 @@
+package linearcode
+
+import "RandomLinearCodePIR/dataobjects"
+
+// PGenerator is a seeded L x K random matrix P whose entries are computed independently from (seed, i, j),
+// so any row or column block is produced on demand without the rows before it.
+// Its P differs from the one of GenerateP for the same seed.
+type PGenerator struct {
+	L     uint32
+	K     uint32
+	Field dataobjects.Field
+	Seed  int64
+}
+
+func NewPGenerator(L, K uint32, field dataobjects.Field, seed int64) *PGenerator {
+	return &PGenerator{L: L, K: K, Field: field, Seed: seed}
+}
+
+// Entry returns P[i][j], a splitmix64 hash of the seed and the position reduced modulo the field size
+// (the bias is below q / 2^64, and there is none for the power-of-two sizes of RingZ2k and BinaryField)
+func (g *PGenerator) Entry(i, j uint32) uint32 {
+	z := uint64(g.Seed) + 0x9e3779b97f4a7c15*(uint64(i)*uint64(g.K)+uint64(j)+1)
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	z ^= z >> 31
+	return uint32(z % dataobjects.FieldSize(g.Field))
+}
+
+// Block returns the rows [rowStart, rowEnd) and columns [colStart, colEnd) of P flattened row by row
+func (g *PGenerator) Block(rowStart, rowEnd, colStart, colEnd uint32) []uint32 {
+	if rowStart > rowEnd || rowEnd > g.L || colStart > colEnd || colEnd > g.K {
+		panic("Block out of the bounds of P")
+	}
+	cols := colEnd - colStart
+	block := dataobjects.AlignedMake[uint32](uint64(rowEnd-rowStart) * uint64(cols))
+	for i := rowStart; i < rowEnd; i++ {
+		row := block[(i-rowStart)*cols : (i-rowStart+1)*cols]
+		for j := colStart; j < colEnd; j++ {
+			row[j-colStart] = g.Entry(i, j)
+		}
+	}
+	return block
+}
+
+// Row returns row i of P
+func (g *PGenerator) Row(i uint32) []uint32 {
+	return g.Block(i, i+1, 0, g.K)
+}
+
+// Column returns column j of P
+func (g *PGenerator) Column(j uint32) []uint32 {
+	return g.Block(0, g.L, j, j+1)
+}
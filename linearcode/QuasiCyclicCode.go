@@ -0,0 +1,127 @@
+package linearcode
+
+import (
+	"RandomLinearCodePIR/dataobjects"
+	"RandomLinearCodePIR/tdm"
+	"math/bits"
+)
+
+// QuasiCyclicCode plays the role of the random code with P made of BlockSize x BlockSize random circulants,
+// truncated to L x K. A circulant times a vector is a cyclic convolution, so EncodeLSN and EncodeDual cost
+// about max(K, L) * log(BlockSize) instead of K * L.
+type QuasiCyclicCode struct {
+	K         uint32
+	L         uint32
+	Field     dataobjects.Field
+	Seed      int64
+	BlockSize uint32
+	root      uint32
+	rowBlocks uint32
+	colBlocks uint32
+	// polys[r*colBlocks+c] is the first column of block (r, c) of P, transposed the first column of its transpose
+	polys      [][]uint32
+	transposed [][]uint32
+}
+
+func NewQuasiCyclicCode(K, L uint32, field dataobjects.Field, seed int64) *QuasiCyclicCode {
+	p := field.GetChar()
+	if p%2 == 0 {
+		panic("QuasiCyclicCode needs an odd characteristic")
+	}
+	// The largest power of two up to min(K, L) with a root of unity of its order
+	b := uint32(1) << bits.Len32(min(K, L)-1)
+	b = min(b, (p-1)&-(p-1))
+
+	qc := &QuasiCyclicCode{K: K, L: L,
+		Field:     field,
+		Seed:      seed,
+		BlockSize: b,
+		root:      tdm.NthRootOfUnity(p, b),
+		rowBlocks: (L + b - 1) / b,
+		colBlocks: (K + b - 1) / b,
+	}
+	qc.polys = GenerateP(qc.rowBlocks*qc.colBlocks, b, field, seed)
+	qc.transposed = make([][]uint32, len(qc.polys))
+	for i, poly := range qc.polys {
+		qc.transposed[i] = dataobjects.AlignedMake[uint32](uint64(b))
+		for t := uint32(0); t < b; t++ {
+			qc.transposed[i][t] = poly[(b-t)%b]
+		}
+	}
+	return qc
+}
+
+// entry returns P[i][j]
+func (qc *QuasiCyclicCode) entry(i, j uint32) uint32 {
+	b := qc.BlockSize
+	return qc.polys[(i/b)*qc.colBlocks+j/b][(i%b+b-j%b)%b]
+}
+
+// As for RandomCode the matrices are those of the code's own seed, the seed argument is not used
+
+// Return -P of C = (-P // I) flattened, L x K
+func (qc *QuasiCyclicCode) Generate1DDualMatrix(L, K uint32, field dataobjects.Field, seed int64) []uint32 {
+	qc.checkDimensions(L, K)
+	vmatrix := dataobjects.AlignedMake[uint32](uint64(K * L))
+	for i := uint32(0); i < L; i++ {
+		for j := uint32(0); j < K; j++ {
+			vmatrix[i*K+j] = qc.Field.Neg(qc.entry(i, j))
+		}
+	}
+	return vmatrix
+}
+
+// Return P^T of D' = (I // P^T) flattened, K x L
+func (qc *QuasiCyclicCode) Generate1DRLCMatrix(L, K uint32, p dataobjects.Field, seed int64) []uint32 {
+	qc.checkDimensions(L, K)
+	vmatrix := dataobjects.AlignedMake[uint32](uint64(K * L))
+	for j := uint32(0); j < K; j++ {
+		for i := uint32(0); i < L; i++ {
+			vmatrix[j*L+i] = qc.entry(i, j)
+		}
+	}
+	return vmatrix
+}
+
+// Dual Code C = (-P // I), returns -P * message for a message of length K
+func (qc *QuasiCyclicCode) EncodeLSN(message []uint32) []uint32 {
+	encoded := qc.circulantMul(qc.polys, qc.rowBlocks, qc.colBlocks, message, qc.K, qc.L, false)
+	qc.Field.NegVector(encoded, 0, uint64(len(encoded)))
+	return encoded
+}
+
+// Code D = (I | P), returns P^T * message for a message of length L
+func (qc *QuasiCyclicCode) EncodeDual(message []uint32) []uint32 {
+	return qc.circulantMul(qc.transposed, qc.colBlocks, qc.rowBlocks, message, qc.L, qc.K, true)
+}
+
+// circulantMul multiplies the outBlocks x inBlocks block matrix by the first inLen symbols of message,
+// block (r, c) is polys[c*outBlocks+r] if transposed, polys[r*inBlocks+c] otherwise
+func (qc *QuasiCyclicCode) circulantMul(polys [][]uint32, outBlocks, inBlocks uint32, message []uint32, inLen, outLen uint32, transposed bool) []uint32 {
+	b := qc.BlockSize
+	q := qc.Field.GetChar()
+
+	// Input blocks padded with zeros, the message is left untouched
+	input := dataobjects.AlignedMake[uint32](uint64(inBlocks * b))
+	copy(input, message[:inLen])
+
+	encoded := dataobjects.AlignedMake[uint32](uint64(outBlocks * b))
+	res := dataobjects.AlignedMake[uint32](uint64(b))
+	for r := uint32(0); r < outBlocks; r++ {
+		for c := uint32(0); c < inBlocks; c++ {
+			poly := polys[r*inBlocks+c]
+			if transposed {
+				poly = polys[c*outBlocks+r]
+			}
+			tdm.NTT_Convolution(poly, input[c*b:(c+1)*b], res, b, qc.root, q)
+			qc.Field.AddVectors(encoded, uint64(r*b), encoded, uint64(r*b), res, 0, uint64(b))
+		}
+	}
+	return encoded[:outLen]
+}
+
+func (qc *QuasiCyclicCode) checkDimensions(L, K uint32) {
+	if L != qc.L || K != qc.K {
+		panic("Matrix dimensions differ from the code's L and K")
+	}
+}
@@ -6,6 +6,79 @@ import (
 	"math/rand"
 )
 
+// RandomCode is the code of the functions below as a LinearCode, holding the P sampled from its seed
+type RandomCode struct {
+	K     uint32
+	L     uint32
+	Field dataobjects.Field
+	Seed  int64
+	P     [][]uint32
+}
+
+func NewRandomCode(K, L uint32, field dataobjects.Field, seed int64) *RandomCode {
+	return &RandomCode{K: K, L: L,
+		Field: field,
+		Seed:  seed,
+		P:     GenerateP(L, K, field, seed),
+	}
+}
+
+// The matrices are those of the code's own seed, as for EvaluationCode the seed argument is not used
+func (rc *RandomCode) Generate1DDualMatrix(L, K uint32, field dataobjects.Field, seed int64) []uint32 {
+	rc.checkDimensions(L, K)
+	vmatrix := dataobjects.AlignedMake[uint32](uint64(K * L))
+	for i := uint32(0); i < L; i++ {
+		for j := uint32(0); j < K; j++ {
+			vmatrix[i*K+j] = rc.Field.Neg(rc.P[i][j])
+		}
+	}
+	return vmatrix
+}
+
+func (rc *RandomCode) Generate1DRLCMatrix(L, K uint32, p dataobjects.Field, seed int64) []uint32 {
+	rc.checkDimensions(L, K)
+	vmatrix := dataobjects.AlignedMake[uint32](uint64(K * L))
+	for j := uint32(0); j < K; j++ {
+		for i := uint32(0); i < L; i++ {
+			vmatrix[j*L+i] = rc.P[i][j]
+		}
+	}
+	return vmatrix
+}
+
+// Dual Code C = (-P // I), returns -P * message for a message of length K
+func (rc *RandomCode) EncodeLSN(message []uint32) []uint32 {
+	encoded := dataobjects.AlignedMake[uint32](uint64(rc.L))
+	for i := uint32(0); i < rc.L; i++ {
+		sum := uint32(0)
+		for j := uint32(0); j < rc.K; j++ {
+			sum = rc.Field.Add(sum, rc.Field.Mul(rc.P[i][j], message[j]))
+		}
+		encoded[i] = rc.Field.Neg(sum)
+	}
+	return encoded
+}
+
+// Code D = (I | P), returns P^T * message for a message of length L
+func (rc *RandomCode) EncodeDual(message []uint32) []uint32 {
+	encoded := dataobjects.AlignedMake[uint32](uint64(rc.K))
+	for i := uint32(0); i < rc.L; i++ {
+		if message[i] == 0 {
+			continue
+		}
+		for j := uint32(0); j < rc.K; j++ {
+			encoded[j] = rc.Field.Add(encoded[j], rc.Field.Mul(rc.P[i][j], message[i]))
+		}
+	}
+	return encoded
+}
+
+func (rc *RandomCode) checkDimensions(L, K uint32) {
+	if L != rc.L || K != rc.K {
+		panic("Matrix dimensions differ from the code's L and K")
+	}
+}
+
 // Return -P of C = (-P // I) flattened
 func Generate1DDualMatrix(L, K uint32, field dataobjects.Field, seed int64) []uint32 {
 	P := GenerateP(L, K, field, seed)
@@ -37,7 +110,12 @@ func GenerateP(L, K uint32, field dataobjects.Field, seed int64) [][]uint32 {
 	for i := uint32(0); i < L; i++ {
 		P[i] = dataobjects.AlignedMake[uint32](uint64(K))
 		if dataobjects.USE_FAST_CODE {
-			utils.RandomizeVectorWithModulus(P[i], K, field.Mod())
+			if size := dataobjects.FieldSize(field); size == 1<<32 {
+				// Every word is an element, there is nothing to reduce
+				utils.RandomizeVector(P[i], K)
+			} else {
+				utils.RandomizeVectorWithModulus(P[i], K, uint32(size))
+			}
 		} else {
 			for j := uint32(0); j < K; j++ {
 				P[i][j] = field.SampleElementWithSeed(rng)
@@ -0,0 +1,94 @@
+package linearcode
+
+import "RandomLinearCodePIR/dataobjects"
+
+// Entries of P generated at a time by the streaming encoders
+const streamBlockEntries = 1 << 16
+
+// StreamingRandomCode is the random code over the P of a PGenerator. The encoders generate P a block
+// of rows at a time, so only the seed has to be kept.
+type StreamingRandomCode struct {
+	*PGenerator
+}
+
+func NewStreamingRandomCode(K, L uint32, field dataobjects.Field, seed int64) *StreamingRandomCode {
+	return &StreamingRandomCode{NewPGenerator(L, K, field, seed)}
+}
+
+// As for RandomCode the matrices are those of the code's own seed, the seed argument is not used
+
+// Return -P of C = (-P // I) flattened, L x K
+func (sc *StreamingRandomCode) Generate1DDualMatrix(L, K uint32, field dataobjects.Field, seed int64) []uint32 {
+	sc.checkDimensions(L, K)
+	vmatrix := sc.Block(0, L, 0, K)
+	sc.Field.NegVector(vmatrix, 0, uint64(len(vmatrix)))
+	return vmatrix
+}
+
+// Return P^T of D' = (I // P^T) flattened, K x L
+func (sc *StreamingRandomCode) Generate1DRLCMatrix(L, K uint32, p dataobjects.Field, seed int64) []uint32 {
+	sc.checkDimensions(L, K)
+	vmatrix := dataobjects.AlignedMake[uint32](uint64(K * L))
+	for j := uint32(0); j < K; j++ {
+		copy(vmatrix[j*L:(j+1)*L], sc.Column(j))
+	}
+	return vmatrix
+}
+
+// Dual Code C = (-P // I), returns -P * message for a message of length K
+func (sc *StreamingRandomCode) EncodeLSN(message []uint32) []uint32 {
+	encoded := dataobjects.AlignedMake[uint32](uint64(sc.L))
+	sc.stream(func(start, end uint32, block []uint32) {
+		for i := start; i < end; i++ {
+			row := block[(i-start)*sc.K : (i-start+1)*sc.K]
+			sum := uint32(0)
+			for j := uint32(0); j < sc.K; j++ {
+				sum = sc.Field.Add(sum, sc.Field.Mul(row[j], message[j]))
+			}
+			encoded[i] = sc.Field.Neg(sum)
+		}
+	})
+	return encoded
+}
+
+// Code D = (I | P), returns P^T * message for a message of length L
+func (sc *StreamingRandomCode) EncodeDual(message []uint32) []uint32 {
+	return sc.EncodeDualRows(message, 1)
+}
+
+// EncodeDualRows returns P^T * x for each row x of the rows x L matrix data, flattened rows x K.
+// Each block of P is generated once for all rows.
+func (sc *StreamingRandomCode) EncodeDualRows(data []uint32, rows uint32) []uint32 {
+	encoded := dataobjects.AlignedMake[uint32](uint64(rows) * uint64(sc.K))
+	sc.stream(func(start, end uint32, block []uint32) {
+		for r := uint32(0); r < rows; r++ {
+			x := data[uint64(r)*uint64(sc.L) : uint64(r+1)*uint64(sc.L)]
+			out := encoded[uint64(r)*uint64(sc.K) : uint64(r+1)*uint64(sc.K)]
+			for i := start; i < end; i++ {
+				if x[i] == 0 {
+					continue
+				}
+				row := block[(i-start)*sc.K : (i-start+1)*sc.K]
+				for j := uint32(0); j < sc.K; j++ {
+					out[j] = sc.Field.Add(out[j], sc.Field.Mul(row[j], x[i]))
+				}
+			}
+		}
+	})
+	return encoded
+}
+
+// stream calls f on consecutive row blocks [start, end) of P
+func (sc *StreamingRandomCode) stream(f func(start, end uint32, block []uint32)) {
+	step := max(1, streamBlockEntries/max(sc.K, 1))
+	for start := uint32(0); start < sc.L; start += step {
+		end := min(start+step, sc.L)
+		f(start, end, sc.Block(start, end, 0, sc.K))
+	}
+}
+
+func (sc *StreamingRandomCode) checkDimensions(L, K uint32) {
+	if L != sc.L || K != sc.K {
+		panic("Matrix dimensions differ from the code's L and K")
+	}
+}
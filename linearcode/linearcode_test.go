@@ -0,0 +1,193 @@
+package linearcode
+
+import (
+	"RandomLinearCodePIR/dataobjects"
+	"RandomLinearCodePIR/utils"
+	"testing"
+)
+
+// matVecProduct returns mat * vec over field for a row x col matrix mat
+func matVecProduct(field dataobjects.Field, mat, vec []uint32, row, col uint32) []uint32 {
+	out := dataobjects.AlignedMake[uint32](uint64(row))
+	for i := uint32(0); i < row; i++ {
+		for j := uint32(0); j < col; j++ {
+			out[i] = field.Add(out[i], field.Mul(mat[i*col+j], vec[j]))
+		}
+	}
+	return out
+}
+
+// Test that the explicit matrices of the evaluation codes agree with their NTT encoders
+func TestEvaluationCodeMatrices(t *testing.T) {
+	p := uint32(65537)
+	field := dataobjects.NewPrimeField(p)
+	k, l := uint32(16), uint32(100)
+
+	code := GetLinearCode(LinearCodeConfig{Name: Vandermonde, K: k, L: l, Field: field})
+	r := field.SampleVector(k)
+	x := field.SampleVector(l)
+
+	lsn := matVecProduct(field, code.Generate1DDualMatrix(l, k, field, 0), r, l, k)
+	dual := matVecProduct(field, code.Generate1DRLCMatrix(l, k, field, 0), x, k, l)
+
+	rCopy, xCopy := append([]uint32{}, r...), append([]uint32{}, x...)
+	encodedLSN := code.EncodeLSN(r)
+	encodedDual := code.EncodeDual(x)
+	for i := range r {
+		if r[i] != rCopy[i] {
+			t.Fatalf("EncodeLSN modified its message at %d", i)
+		}
+	}
+	for i := range x {
+		if x[i] != xCopy[i] {
+			t.Fatalf("EncodeDual modified its message at %d", i)
+		}
+	}
+	for i := range lsn {
+		if lsn[i] != encodedLSN[i] {
+			t.Fatalf("dual matrix differs from EncodeLSN at %d", i)
+		}
+	}
+	for i := range dual {
+		if dual[i] != encodedDual[i] {
+			t.Fatalf("RLC matrix differs from EncodeDual at %d", i)
+		}
+	}
+
+	ext := dataobjects.NewExtensionField(17, 2)
+	d := ext.Degree()
+	k, l = 4, 20
+	extCode := GetLinearCode(LinearCodeConfig{Name: ExtensionVandermonde, K: k, L: l, Field: dataobjects.NewPrimeField(17), Degree: d})
+	message := utils.RandomPrimeFieldVector(k*d, 17)
+
+	vmatrix := extCode.Generate1DDualMatrix(l, k, ext, 0)
+	encoded := extCode.EncodeLSN(message)
+	term, sum := ext.NewElement(), ext.NewElement()
+	for i := uint32(0); i < l; i++ {
+		copy(sum, ext.NewElement())
+		for j := uint32(0); j < k; j++ {
+			ext.MulElement(term, vmatrix[(i*k+j)*d:(i*k+j+1)*d], message[j*d:(j+1)*d])
+			ext.AddElement(sum, sum, term)
+		}
+		for c := uint32(0); c < d; c++ {
+			if sum[c] != encoded[i*d+c] {
+				t.Fatalf("extension dual matrix differs from EncodeLSN at %d", i)
+			}
+		}
+	}
+}
+
+// checkLinearCodeEncoders checks EncodeLSN and EncodeDual against the matrices -P and P^T of the code
+func checkLinearCodeEncoders(t *testing.T, code LinearCode, k, l uint32, field dataobjects.Field) {
+	r, x := field.SampleVector(k), field.SampleVector(l)
+	lsn, dual := code.EncodeLSN(r), code.EncodeDual(x)
+	dualMatrix := code.Generate1DDualMatrix(l, k, field, 5)
+	rlcMatrix := code.Generate1DRLCMatrix(l, k, field, 5)
+	for i := uint32(0); i < l; i++ {
+		sum := uint32(0)
+		for j := uint32(0); j < k; j++ {
+			sum = field.Add(sum, field.Mul(dualMatrix[i*k+j], r[j]))
+			if rlcMatrix[j*l+i] != field.Neg(dualMatrix[i*k+j]) {
+				t.Fatalf("K %d L %d: matrices are not -P and P^T at (%d, %d)", k, l, i, j)
+			}
+		}
+		if sum != lsn[i] {
+			t.Fatalf("K %d L %d: EncodeLSN differs from the dual matrix at %d", k, l, i)
+		}
+	}
+	for j := uint32(0); j < k; j++ {
+		sum := uint32(0)
+		for i := uint32(0); i < l; i++ {
+			sum = field.Add(sum, field.Mul(rlcMatrix[j*l+i], x[i]))
+		}
+		if sum != dual[j] {
+			t.Fatalf("K %d L %d: EncodeDual differs from the RLC matrix at %d", k, l, j)
+		}
+	}
+}
+
+// Test the quasi-cyclic encoders against its matrices for several shapes
+func TestQuasiCyclicCode(t *testing.T) {
+	field := dataobjects.NewPrimeField(65537)
+	for _, shape := range [][2]uint32{{8, 64}, {64, 8}, {5, 37}, {37, 37}} {
+		checkLinearCodeEncoders(t, NewQuasiCyclicCode(shape[0], shape[1], field, 5), shape[0], shape[1], field)
+	}
+}
+
+// Test the streaming P against its blocks and the streaming encoders against the matrices
+func TestStreamingRandomCode(t *testing.T) {
+	field := dataobjects.NewPrimeField(65537)
+	for _, shape := range [][2]uint32{{8, 64}, {64, 8}, {1 << 12, 37}} {
+		k, l := shape[0], shape[1]
+		code := NewStreamingRandomCode(k, l, field, 5)
+		checkLinearCodeEncoders(t, code, k, l, field)
+
+		block := code.Block(3, l, 1, k)
+		for i := uint32(3); i < l; i++ {
+			for j := uint32(1); j < k; j++ {
+				if block[(i-3)*(k-1)+j-1] != code.Entry(i, j) || code.Entry(i, j) >= 65537 {
+					t.Fatalf("K %d L %d: block differs from P at (%d, %d)", k, l, i, j)
+				}
+			}
+		}
+
+		rows := utils.RandomPrimeFieldVector(3*l, 65537)
+		dual := code.EncodeDualRows(rows, 3)
+		for r := uint32(0); r < 3; r++ {
+			single := code.EncodeDual(rows[r*l : (r+1)*l])
+			for j := uint32(0); j < k; j++ {
+				if dual[r*k+j] != single[j] {
+					t.Fatalf("K %d L %d: EncodeDualRows differs from EncodeDual at row %d", k, l, r)
+				}
+			}
+		}
+	}
+	if NewPGenerator(8, 8, field, 1).Entry(0, 0) == NewPGenerator(8, 8, field, 2).Entry(0, 0) {
+		t.Fatalf("P does not depend on the seed")
+	}
+
+	// The fields of 2^32 elements have Mod 0, their entries are whole words
+	for _, field := range []dataobjects.Field{dataobjects.NewRingZ2k(32), dataobjects.NewBinaryField(32)} {
+		code := NewStreamingRandomCode(8, 64, field, 5)
+		checkLinearCodeEncoders(t, code, 8, 64, field)
+		high := false
+		for _, x := range code.Block(0, 64, 0, 8) {
+			high = high || x >= 1<<31
+		}
+		if !high {
+			t.Fatalf("%T: entries of P miss the high half of the field", field)
+		}
+	}
+}
+
+// Test the duality check and the distance estimates of the linear codes
+func TestLinearCodeAnalysis(t *testing.T) {
+	field := dataobjects.NewPrimeField(65537)
+	options := DefaultAnalysisOptions()
+
+	for _, name := range []string{Random, QuasiCyclic, StreamingRandom} {
+		analysis, err := AnalyzeLinearCode(LinearCodeConfig{Name: name, K: 3, L: 5, Field: field, Seed: 1}, options)
+		if err != nil || !analysis.Dual || analysis.Degenerate {
+			t.Fatalf("%s: %v %v", name, err, analysis)
+		}
+		if !analysis.DistanceExact || !analysis.DualDistanceExact || analysis.Distance > 4 || analysis.DualDistance > 6 {
+			t.Fatalf("%s: distances beyond the Singleton bound %v", name, analysis)
+		}
+	}
+
+	analysis, err := AnalyzeLinearCode(LinearCodeConfig{Name: Random, K: 16, L: 64, Field: field, Seed: 1}, options)
+	if err != nil || !analysis.Dual || analysis.DistanceExact || analysis.DualDistanceExact || analysis.Distance > 17 || analysis.DualDistance > 65 {
+		t.Fatalf("sampled: %v %v", err, analysis)
+	}
+
+	// The Vandermonde code on a subgroup is not MDS
+	options.Slack = 0
+	analysis, err = AnalyzeLinearCode(LinearCodeConfig{Name: Vandermonde, K: 3, L: 5, Field: field}, options)
+	if err != nil || !analysis.Dual || !analysis.Degenerate {
+		t.Fatalf("Vandermonde: %v %v", err, analysis)
+	}
+
+	if _, err := AnalyzeLinearCode(LinearCodeConfig{Name: ExtensionVandermonde, K: 3, L: 5, Field: field, Degree: 2}, options); err == nil {
+		t.Fatalf("code over an extension field analyzed")
+	}
+}
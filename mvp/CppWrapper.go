@@ -1,3 +1,5 @@
+//go:build cgo && !purego
+
 package mvp
 
 /*
@@ -25,6 +27,18 @@ func BlockMatVecProduct(mat, vec, out []uint32, row, col, numBlock, p uint32) {
 	runtime.KeepAlive(out)
 }
 
+func BlockMatMatProduct(mat, vecs, out []uint32, row, col, numBlock, numVec, p uint32) {
+	C.BlockMatMatProduct(
+		(*C.uint32_t)(unsafe.Pointer(&mat[0])),
+		(*C.uint32_t)(unsafe.Pointer(&vecs[0])),
+		(*C.uint32_t)(unsafe.Pointer(&out[0])),
+		C.uint32_t(row), C.uint32_t(col), C.uint32_t(numBlock), C.uint32_t(numVec), C.uint32_t(p),
+	)
+	runtime.KeepAlive(mat)
+	runtime.KeepAlive(vecs)
+	runtime.KeepAlive(out)
+}
+
 func MatVecProduct(mat, vec, out []uint32, row, col, p uint32) {
 	C.MatVecProduct(
 		(*C.uint32_t)(unsafe.Pointer(&mat[0])),
@@ -56,3 +70,64 @@ func TransformToBlockwise(mat, matBlocked []uint32, n, m, s uint32) {
 		C.uint32_t(n), C.uint32_t(m), C.uint32_t(s),
 	)
 }
+
+// TransformFromBlockwise is the inverse of TransformToBlockwise
+func TransformFromBlockwise(matBlocked, mat []uint32, n, m, s uint32) {
+	C.TransformBlockRowMajorToRowMajor(
+		(*C.uint32_t)(unsafe.Pointer(&matBlocked[0])),
+		(*C.uint32_t)(unsafe.Pointer(&mat[0])),
+		C.uint32_t(n), C.uint32_t(m), C.uint32_t(s),
+	)
+}
+
+func BlockMatVecProduct64(mat, vec, out []uint64, row, col, numBlock uint32, p uint64) {
+	C.BlockMatVecProduct64(
+		(*C.uint64_t)(unsafe.Pointer(&mat[0])),
+		(*C.uint64_t)(unsafe.Pointer(&vec[0])),
+		(*C.uint64_t)(unsafe.Pointer(&out[0])),
+		C.uint32_t(row), C.uint32_t(col), C.uint32_t(numBlock), C.uint64_t(p),
+	)
+	runtime.KeepAlive(mat)
+	runtime.KeepAlive(vec)
+	runtime.KeepAlive(out)
+}
+
+func MatVecProduct64(mat, vec, out []uint64, row, col uint32, p uint64) {
+	C.MatVecProduct64(
+		(*C.uint64_t)(unsafe.Pointer(&mat[0])),
+		(*C.uint64_t)(unsafe.Pointer(&vec[0])),
+		(*C.uint64_t)(unsafe.Pointer(&out[0])),
+		C.uint32_t(row), C.uint32_t(col), C.uint64_t(p),
+	)
+	runtime.KeepAlive(mat)
+	runtime.KeepAlive(vec)
+	runtime.KeepAlive(out)
+}
+
+func BlockVecMatProduct64(mat, vec, out []uint64, row, col, numBlock uint32, p uint64) {
+	C.BlockVecMatProduct64(
+		(*C.uint64_t)(unsafe.Pointer(&mat[0])),
+		(*C.uint64_t)(unsafe.Pointer(&vec[0])),
+		(*C.uint64_t)(unsafe.Pointer(&out[0])),
+		C.uint32_t(row), C.uint32_t(col), C.uint32_t(numBlock), C.uint64_t(p),
+	)
+	runtime.KeepAlive(mat)
+	runtime.KeepAlive(vec)
+	runtime.KeepAlive(out)
+}
+
+func TransformToBlockwise64(mat, matBlocked []uint64, n, m, s uint32) {
+	C.TransformRowMajorToBlockRowMajor64(
+		(*C.uint64_t)(unsafe.Pointer(&mat[0])),
+		(*C.uint64_t)(unsafe.Pointer(&matBlocked[0])),
+		C.uint32_t(n), C.uint32_t(m), C.uint32_t(s),
+	)
+}
+
+func TransformFromBlockwise64(matBlocked, mat []uint64, n, m, s uint32) {
+	C.TransformBlockRowMajorToRowMajor64(
+		(*C.uint64_t)(unsafe.Pointer(&matBlocked[0])),
+		(*C.uint64_t)(unsafe.Pointer(&mat[0])),
+		C.uint32_t(n), C.uint32_t(m), C.uint32_t(s),
+	)
+}
@@ -0,0 +1,237 @@
+//go:build purego || !cgo
+
+package mvp
+
+import "math/bits"
+
+// Go implementations of the kernels in mvp.cpp and matrixShapeTransform.cpp, used when building
+// with the purego tag or CGO_ENABLED=0. The 32-bit products accumulate in uint64 without
+// intermediate reduction exactly like the C code, so both builds give identical results.
+
+// mat is stored block-wise, see TransformToBlockwise. out[blk*row + i] is row i of block blk.
+func BlockMatVecProduct(mat, vec, out []uint32, row, col, numBlock, p uint32) {
+	if col%numBlock != 0 {
+		panic("the number of columns must be a multiple of the number of blocks")
+	}
+	b := col / numBlock
+
+	for blk := uint32(0); blk < numBlock; blk++ {
+		matBlk := mat[uint64(blk)*uint64(row)*uint64(b):]
+		MatVecProduct(matBlk, vec[blk*b:], out[blk*row:], row, b, p)
+	}
+}
+
+// numVec vectors of length col back to back in vecs, out[v*numBlock*row:] is the output of
+// BlockMatVecProduct for vector v. Each row of mat is read once for all vectors.
+func BlockMatMatProduct(mat, vecs, out []uint32, row, col, numBlock, numVec, p uint32) {
+	if col%numBlock != 0 {
+		panic("the number of columns must be a multiple of the number of blocks")
+	}
+	b := col / numBlock
+
+	for blk := uint32(0); blk < numBlock; blk++ {
+		matBlk := mat[uint64(blk)*uint64(row)*uint64(b):]
+		for r := uint32(0); r < row; r++ {
+			rowPtr := matBlk[uint64(r)*uint64(b) : uint64(r+1)*uint64(b)]
+			for v := uint32(0); v < numVec; v++ {
+				vecBlk := vecs[uint64(v)*uint64(col)+uint64(blk*b):]
+
+				acc := uint64(0)
+				for c, x := range rowPtr {
+					acc += uint64(x) * uint64(vecBlk[c])
+				}
+				out[uint64(v)*uint64(numBlock)*uint64(row)+uint64(blk)*uint64(row)+uint64(r)] = uint32(acc % uint64(p))
+			}
+		}
+	}
+}
+
+func MatVecProduct(mat, vec, out []uint32, row, col, p uint32) {
+	for r := uint32(0); r < row; r++ {
+		rowPtr := mat[uint64(r)*uint64(col) : uint64(r+1)*uint64(col)]
+
+		acc := uint64(0)
+		for c, v := range rowPtr {
+			acc += uint64(v) * uint64(vec[c])
+		}
+
+		out[r] = uint32(acc % uint64(p))
+	}
+}
+
+// out[blk*col + j] = sum over the rows i of block blk of vec[i] * mat[i][j]
+func BlockVecMatProduct(mat, vec, out []uint32, row, col, numBlock, p uint32) {
+	b := row / numBlock
+	acc := make([]uint64, col)
+
+	for blk := uint32(0); blk < numBlock; blk++ {
+		clear(acc)
+
+		for i := blk * b; i < (blk+1)*b; i++ {
+			v := uint64(vec[i])
+			rowPtr := mat[uint64(i)*uint64(col) : uint64(i+1)*uint64(col)]
+			for c := range rowPtr {
+				acc[c] += uint64(rowPtr[c]) * v
+			}
+		}
+
+		res := out[uint64(blk)*uint64(col):]
+		for c := range acc {
+			res[c] = uint32(acc[c] % uint64(p))
+		}
+	}
+}
+
+func TransformToBlockwise(mat, matBlocked []uint32, n, m, s uint32) {
+	if m%s != 0 {
+		panic("the number of columns must be a multiple of the number of blocks")
+	}
+	b := m / s
+
+	for row := uint32(0); row < n; row++ {
+		for blk := uint32(0); blk < s; blk++ {
+			dst := (uint64(blk)*uint64(n) + uint64(row)) * uint64(b)
+			src := uint64(row)*uint64(m) + uint64(blk)*uint64(b)
+			copy(matBlocked[dst:dst+uint64(b)], mat[src:src+uint64(b)])
+		}
+	}
+}
+
+// TransformFromBlockwise is the inverse of TransformToBlockwise
+func TransformFromBlockwise(matBlocked, mat []uint32, n, m, s uint32) {
+	if m%s != 0 {
+		panic("the number of columns must be a multiple of the number of blocks")
+	}
+	b := m / s
+
+	for row := uint32(0); row < n; row++ {
+		for blk := uint32(0); blk < s; blk++ {
+			src := (uint64(blk)*uint64(n) + uint64(row)) * uint64(b)
+			dst := uint64(row)*uint64(m) + uint64(blk)*uint64(b)
+			copy(mat[dst:dst+uint64(b)], matBlocked[src:src+uint64(b)])
+		}
+	}
+}
+
+// acc128 is a 128-bit accumulator for the 64-bit kernels
+type acc128 struct {
+	hi, lo uint64
+}
+
+func (a *acc128) addMul(x, y uint64) {
+	hi, lo := bits.Mul64(x, y)
+	var carry uint64
+	a.lo, carry = bits.Add64(a.lo, lo, 0)
+	a.hi, _ = bits.Add64(a.hi, hi, carry)
+}
+
+func (a *acc128) mod(p uint64) uint64 {
+	_, rem := bits.Div64(a.hi%p, a.lo, p)
+	return rem
+}
+
+// Number of products below 2^(2L) that fit on top of an accumulator < p, as in SafeTerms64
+func safeTerms64(p uint64) uint32 {
+	L := bits.Len64((p - 1) | 1)
+	if 2*L >= 127 {
+		return 1
+	}
+	room := 128 - 2*L
+	if room > 31 {
+		return 1 << 31
+	}
+	return (1 << room) - 1
+}
+
+func MatVecProduct64(mat, vec, out []uint64, row, col uint32, p uint64) {
+	chunk := safeTerms64(p)
+
+	for r := uint32(0); r < row; r++ {
+		rowPtr := mat[uint64(r)*uint64(col) : uint64(r+1)*uint64(col)]
+
+		var acc acc128
+		terms := uint32(0)
+		for c, v := range rowPtr {
+			acc.addMul(v, vec[c])
+			if terms++; terms == chunk {
+				acc = acc128{lo: acc.mod(p)}
+				terms = 0
+			}
+		}
+
+		out[r] = acc.mod(p)
+	}
+}
+
+func BlockMatVecProduct64(mat, vec, out []uint64, row, col, numBlock uint32, p uint64) {
+	if col%numBlock != 0 {
+		panic("the number of columns must be a multiple of the number of blocks")
+	}
+	b := col / numBlock
+
+	for blk := uint32(0); blk < numBlock; blk++ {
+		matBlk := mat[uint64(blk)*uint64(row)*uint64(b):]
+		MatVecProduct64(matBlk, vec[blk*b:], out[blk*row:], row, b, p)
+	}
+}
+
+func BlockVecMatProduct64(mat, vec, out []uint64, row, col, numBlock uint32, p uint64) {
+	chunk := safeTerms64(p)
+	b := row / numBlock
+	acc := make([]acc128, col)
+
+	for blk := uint32(0); blk < numBlock; blk++ {
+		clear(acc)
+
+		terms := uint32(0)
+		for i := blk * b; i < (blk+1)*b; i++ {
+			v := vec[i]
+			rowPtr := mat[uint64(i)*uint64(col) : uint64(i+1)*uint64(col)]
+			for c := range rowPtr {
+				acc[c].addMul(rowPtr[c], v)
+			}
+
+			if terms++; terms == chunk {
+				for c := range acc {
+					acc[c] = acc128{lo: acc[c].mod(p)}
+				}
+				terms = 0
+			}
+		}
+
+		res := out[uint64(blk)*uint64(col):]
+		for c := range acc {
+			res[c] = acc[c].mod(p)
+		}
+	}
+}
+
+func TransformToBlockwise64(mat, matBlocked []uint64, n, m, s uint32) {
+	if m%s != 0 {
+		panic("the number of columns must be a multiple of the number of blocks")
+	}
+	b := m / s
+
+	for row := uint32(0); row < n; row++ {
+		for blk := uint32(0); blk < s; blk++ {
+			dst := (uint64(blk)*uint64(n) + uint64(row)) * uint64(b)
+			src := uint64(row)*uint64(m) + uint64(blk)*uint64(b)
+			copy(matBlocked[dst:dst+uint64(b)], mat[src:src+uint64(b)])
+		}
+	}
+}
+
+func TransformFromBlockwise64(matBlocked, mat []uint64, n, m, s uint32) {
+	if m%s != 0 {
+		panic("the number of columns must be a multiple of the number of blocks")
+	}
+	b := m / s
+
+	for row := uint32(0); row < n; row++ {
+		for blk := uint32(0); blk < s; blk++ {
+			src := (uint64(blk)*uint64(n) + uint64(row)) * uint64(b)
+			dst := uint64(row)*uint64(m) + uint64(blk)*uint64(b)
+			copy(mat[dst:dst+uint64(b)], matBlocked[src:src+uint64(b)])
+		}
+	}
+}
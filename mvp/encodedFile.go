@@ -0,0 +1,172 @@
+package mvp
+
+import (
+	"RandomLinearCodePIR/dataobjects"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"math/bits"
+)
+
+// Scheme names recorded in the header of a saved encoded database
+const (
+	SlsnScheme     = "SlsnMVP"
+	RingSlsnScheme = "RingSlsnMVP"
+	LpnScheme      = "LpnMVP"
+)
+
+// fieldParam records the type of the field and its parameters beyond P, so that a database encoded over
+// another field with the same P, e.g. GF(2^8) with another polynomial, is refused
+func fieldParam(field dataobjects.Field) dataobjects.Param {
+	value := fmt.Sprintf("%T", field)
+	switch f := field.(type) {
+	case *dataobjects.RingZ2k:
+		value += fmt.Sprintf(" k %d", bits.TrailingZeros64(dataobjects.FieldSize(f)))
+	case *dataobjects.BinaryField:
+		value += fmt.Sprintf(" poly %#x", f.Poly())
+	case *dataobjects.ExtensionField:
+		value += fmt.Sprintf(" p %d modulus %v", f.Base().GetChar(), f.Modulus())
+	}
+	return dataobjects.Param{Name: "Field", Value: value}
+}
+
+// Only the params that determine the encoded matrix are recorded, Epsi only affects queries
+func (params SlsnParams) fileParams() []dataobjects.Param {
+	fileParams := []dataobjects.Param{
+		fieldParam(params.Field),
+		dataobjects.UintParam("P", params.P),
+		dataobjects.UintParam("S", params.S),
+		dataobjects.UintParam("B", params.B),
+		dataobjects.UintParam("K", params.K),
+		dataobjects.UintParam("L", params.L),
+		dataobjects.UintParam("N", params.N),
+		dataobjects.UintParam("M", params.M),
+	}
+	// Only recorded when set, so files of the default random code keep their params
+	if params.LinearCode != "" {
+		fileParams = append(fileParams, dataobjects.Param{Name: "LinearCode", Value: params.LinearCode})
+	}
+	return fileParams
+}
+
+func (params LpnParams) fileParams() []dataobjects.Param {
+	fileParams := []dataobjects.Param{
+		fieldParam(params.Field),
+		dataobjects.UintParam("P", params.P),
+		dataobjects.UintParam("N", params.N),
+		dataobjects.UintParam("M", params.M),
+		dataobjects.UintParam("L", params.L),
+		dataobjects.UintParam("K", params.K),
+		dataobjects.UintParam("M_1", params.M_1),
+		dataobjects.UintParam("ECCLength", params.ECCLength),
+		{Name: "ECCName", Value: params.ECCName},
+	}
+	// Only recorded when set, so files of codes on the default points keep their params. The points are
+	// recorded by checksum, a header param cannot hold thousands of them.
+	if len(params.ECCPoints) > 0 {
+		buf := make([]byte, 4*len(params.ECCPoints))
+		for i, a := range params.ECCPoints {
+			binary.LittleEndian.PutUint32(buf[4*i:], a)
+		}
+		checksum := crc32.Checksum(buf, crc32.MakeTable(crc32.Castagnoli))
+		fileParams = append(fileParams, dataobjects.Param{
+			Name:  "ECCPoints",
+			Value: fmt.Sprintf("%d points, crc32c %08x", len(params.ECCPoints), checksum),
+		})
+	}
+	return fileParams
+}
+
+// encodedLayout returns the arguments of AssertLayout for the output of Encode
+func (params SlsnParams) encodedLayout() (dataobjects.Layout, uint32, uint32, uint32) {
+	return dataobjects.BlockRowMajor, params.M, params.N, params.S
+}
+
+func (params LpnParams) encodedLayout() (dataobjects.Layout, uint32, uint32, uint32) {
+	return dataobjects.SlicedRowMajor, params.M / params.M_1 * params.ECCLength, params.N, params.ECCLength
+}
+
+func saveEncoded(path, scheme string, params []dataobjects.Param, encoded *dataobjects.Matrix,
+	layout dataobjects.Layout, rows, cols, blocks uint32) error {
+	if err := encoded.CheckLayout(layout, rows, cols, blocks); err != nil {
+		return err
+	}
+	return dataobjects.SaveMatrix(path, scheme, params, encoded)
+}
+
+func loadEncoded(path, scheme string, params []dataobjects.Param,
+	layout dataobjects.Layout, rows, cols, blocks uint32) (*dataobjects.Matrix, error) {
+	m, err := dataobjects.LoadMatrix(path, scheme, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.CheckLayout(layout, rows, cols, blocks); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func mapEncoded(path, scheme string, params []dataobjects.Param,
+	layout dataobjects.Layout, rows, cols, blocks uint32) (*dataobjects.MappedMatrix, error) {
+	mm, err := dataobjects.MapMatrix(path, scheme, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := mm.CheckLayout(layout, rows, cols, blocks); err != nil {
+		mm.Close()
+		return nil, err
+	}
+	return mm, nil
+}
+
+// SaveEncoded writes the output of Encode to path
+func (slsn *SlsnMVP) SaveEncoded(path string, encoded *dataobjects.Matrix) error {
+	layout, rows, cols, blocks := slsn.Params.encodedLayout()
+	return saveEncoded(path, SlsnScheme, slsn.Params.fileParams(), encoded, layout, rows, cols, blocks)
+}
+
+// LoadEncoded reads a database saved by SaveEncoded with the same params, ready for Answer
+func (slsn *SlsnMVP) LoadEncoded(path string) (*dataobjects.Matrix, error) {
+	layout, rows, cols, blocks := slsn.Params.encodedLayout()
+	return loadEncoded(path, SlsnScheme, slsn.Params.fileParams(), layout, rows, cols, blocks)
+}
+
+// MapEncoded maps a database saved by SaveEncoded instead of reading it, pass its Matrix to Answer and
+// Close it when done
+func (slsn *SlsnMVP) MapEncoded(path string) (*dataobjects.MappedMatrix, error) {
+	layout, rows, cols, blocks := slsn.Params.encodedLayout()
+	return mapEncoded(path, SlsnScheme, slsn.Params.fileParams(), layout, rows, cols, blocks)
+}
+
+func (rmvp *RingSlsnMVP) SaveEncoded(path string, encoded *dataobjects.Matrix) error {
+	params := rmvp.SlsnMVP.Params
+	layout, rows, cols, blocks := params.encodedLayout()
+	return saveEncoded(path, RingSlsnScheme, params.fileParams(), encoded, layout, rows, cols, blocks)
+}
+
+func (rmvp *RingSlsnMVP) LoadEncoded(path string) (*dataobjects.Matrix, error) {
+	params := rmvp.SlsnMVP.Params
+	layout, rows, cols, blocks := params.encodedLayout()
+	return loadEncoded(path, RingSlsnScheme, params.fileParams(), layout, rows, cols, blocks)
+}
+
+func (rmvp *RingSlsnMVP) MapEncoded(path string) (*dataobjects.MappedMatrix, error) {
+	params := rmvp.SlsnMVP.Params
+	layout, rows, cols, blocks := params.encodedLayout()
+	return mapEncoded(path, RingSlsnScheme, params.fileParams(), layout, rows, cols, blocks)
+}
+
+func (lpn *LpnMVP) SaveEncoded(path string, encoded *dataobjects.Matrix) error {
+	layout, rows, cols, blocks := lpn.Params.encodedLayout()
+	return saveEncoded(path, LpnScheme, lpn.Params.fileParams(), encoded, layout, rows, cols, blocks)
+}
+
+func (lpn *LpnMVP) LoadEncoded(path string) (*dataobjects.Matrix, error) {
+	layout, rows, cols, blocks := lpn.Params.encodedLayout()
+	return loadEncoded(path, LpnScheme, lpn.Params.fileParams(), layout, rows, cols, blocks)
+}
+
+func (lpn *LpnMVP) MapEncoded(path string) (*dataobjects.MappedMatrix, error) {
+	layout, rows, cols, blocks := lpn.Params.encodedLayout()
+	return mapEncoded(path, LpnScheme, lpn.Params.fileParams(), layout, rows, cols, blocks)
+}
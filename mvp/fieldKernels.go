@@ -0,0 +1,101 @@
+package mvp
+
+import "RandomLinearCodePIR/dataobjects"
+
+// The native kernels reduce modulo the prime P. Over the other fields SlsnMVP supports, RingZ2k and
+// BinaryField, the kernels below compute the same products through the Field, with the same layouts.
+
+// nativeKernels reports whether the native kernels compute over field
+func nativeKernels(field dataobjects.Field) bool {
+	_, ok := field.(*dataobjects.PrimeField)
+	return ok
+}
+
+// supportedField reports whether SlsnMVP computes over field, natively or through the field kernels
+func supportedField(field dataobjects.Field) bool {
+	switch field.(type) {
+	case *dataobjects.PrimeField, *dataobjects.RingZ2k, *dataobjects.BinaryField:
+		return true
+	}
+	return false
+}
+
+func fieldMatVecProduct(field dataobjects.Field, mat, vec, out []uint32, row, col uint32) {
+	for r := uint32(0); r < row; r++ {
+		rowPtr := mat[uint64(r)*uint64(col) : uint64(r+1)*uint64(col)]
+
+		acc := uint32(0)
+		for c, v := range rowPtr {
+			acc = field.Add(acc, field.Mul(v, vec[c]))
+		}
+		out[r] = acc
+	}
+}
+
+func fieldBlockMatVecProduct(field dataobjects.Field, mat, vec, out []uint32, row, col, numBlock uint32) {
+	if col%numBlock != 0 {
+		panic("the number of columns must be a multiple of the number of blocks")
+	}
+	b := col / numBlock
+
+	for blk := uint32(0); blk < numBlock; blk++ {
+		matBlk := mat[uint64(blk)*uint64(row)*uint64(b):]
+		fieldMatVecProduct(field, matBlk, vec[blk*b:], out[blk*row:], row, b)
+	}
+}
+
+func fieldBlockMatMatProduct(field dataobjects.Field, mat, vecs, out []uint32, row, col, numBlock, numVec uint32) {
+	size := uint64(numBlock) * uint64(row)
+	for v := uint32(0); v < numVec; v++ {
+		fieldBlockMatVecProduct(field, mat, vecs[uint64(v)*uint64(col):], out[uint64(v)*size:], row, col, numBlock)
+	}
+}
+
+func fieldBlockVecMatProduct(field dataobjects.Field, mat, vec, out []uint32, row, col, numBlock uint32) {
+	b := row / numBlock
+
+	for blk := uint32(0); blk < numBlock; blk++ {
+		res := out[uint64(blk)*uint64(col) : uint64(blk+1)*uint64(col)]
+		clear(res)
+		for i := blk * b; i < (blk+1)*b; i++ {
+			rowPtr := mat[uint64(i)*uint64(col) : uint64(i+1)*uint64(col)]
+			for c := range rowPtr {
+				res[c] = field.Add(res[c], field.Mul(rowPtr[c], vec[i]))
+			}
+		}
+	}
+}
+
+// The products of SlsnMVP, native over the prime field of P and through Field otherwise
+
+func (params SlsnParams) matVecProduct(mat, vec, out []uint32, row, col uint32) {
+	if nativeKernels(params.Field) {
+		MatVecProduct(mat, vec, out, row, col, params.P)
+	} else {
+		fieldMatVecProduct(params.Field, mat, vec, out, row, col)
+	}
+}
+
+func (params SlsnParams) blockMatVecProduct(mat, vec, out []uint32, row, col, numBlock uint32) {
+	if nativeKernels(params.Field) {
+		BlockMatVecProduct(mat, vec, out, row, col, numBlock, params.P)
+	} else {
+		fieldBlockMatVecProduct(params.Field, mat, vec, out, row, col, numBlock)
+	}
+}
+
+func (params SlsnParams) blockMatMatProduct(mat, vecs, out []uint32, row, col, numBlock, numVec uint32) {
+	if nativeKernels(params.Field) {
+		BlockMatMatProduct(mat, vecs, out, row, col, numBlock, numVec, params.P)
+	} else {
+		fieldBlockMatMatProduct(params.Field, mat, vecs, out, row, col, numBlock, numVec)
+	}
+}
+
+func (params SlsnParams) blockVecMatProduct(mat, vec, out []uint32, row, col, numBlock uint32) {
+	if nativeKernels(params.Field) {
+		BlockVecMatProduct(mat, vec, out, row, col, numBlock, params.P)
+	} else {
+		fieldBlockVecMatProduct(params.Field, mat, vec, out, row, col, numBlock)
+	}
+}
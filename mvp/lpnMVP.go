@@ -23,6 +23,31 @@ type LpnParams struct {
 	P         uint32
 	ECCLength uint32
 	ECCName   string
+	// Evaluation points of a Reed-Solomon ECCName, see ecc.ECCConfig.Points
+	ECCPoints []uint32
+	// Goroutines Answer splits the rows of all slices over, serial if at most 1. The answers do not depend on it.
+	Workers uint32
+}
+
+// DecodingFailureProbability returns the probability that Decode fails because too many slice queries are
+// noisy, an upper bound for codes that are not MDS (see ecc.CodeFailureProbability)
+func (params LpnParams) DecodingFailureProbability() float64 {
+	return ecc.CodeFailureProbability(ecc.GetECCCode(params.eccConfig()), params.Epsi, params.L)
+}
+
+// eccConfig panics for ExtensionReedSolomon: each slice answer is a vector over F_P, one symbol per slice,
+// so a code over GF(P^d) does not fit the slicing
+func (params LpnParams) eccConfig() ecc.ECCConfig {
+	if params.ECCName == ecc.ExtensionReedSolomon {
+		panic("LpnMVP does not support " + ecc.ExtensionReedSolomon + ", its symbols are single F_P elements")
+	}
+	return ecc.ECCConfig{
+		Name:   params.ECCName,
+		Q:      params.P,
+		N:      params.ECCLength,
+		K:      params.M_1,
+		Points: params.ECCPoints,
+	}
 }
 
 type LpnQuery struct {
@@ -80,13 +105,9 @@ func (lpn *LpnMVP) Encode(sk SecretKey, input dataobjects.Matrix, masks [][]uint
 	encoded := dataobjects.AlignedMake[uint32](uint64(entryPerSlice * params.ECCLength))
 
 	// Re-use slot for ECC encoding
-	message := dataobjects.AlignedMake[uint32](uint64(params.ECCLength))
+	message := dataobjects.AlignedMake[uint32](uint64(params.M_1))
 
-	generatorMatrix := ecc.GetECCCode(ecc.ECCConfig{
-		Name: params.ECCName,
-		Q:    params.P,
-		N:    params.ECCLength,
-		K:    params.M_1}).GetGeneratorMatrix(params.M_1, params.ECCLength, params.P)
+	ecccode := ecc.GetECCCode(params.eccConfig())
 
 	for i := uint32(0); i < rowPerSlice; i++ {
 		for j := uint32(0); j < params.M_1; j++ {
@@ -110,11 +131,11 @@ func (lpn *LpnMVP) Encode(sk SecretKey, input dataobjects.Matrix, masks [][]uint
 				message[t] = encoded[t*entryPerSlice+i*params.N+j]
 			}
 
-			MatVecProduct(generatorMatrix, message, message[params.M_1:], params.ECCLength, params.M_1, params.P)
+			codeword := ecccode.Encode(message)
 
 			// Put to the M_1:ECCLength slice
 			for t := params.M_1; t < params.ECCLength; t++ {
-				encoded[t*entryPerSlice+i*params.N+j] = message[t]
+				encoded[t*entryPerSlice+i*params.N+j] = codeword[t]
 			}
 		}
 	}
@@ -127,9 +148,12 @@ func (lpn *LpnMVP) Encode(sk SecretKey, input dataobjects.Matrix, masks [][]uint
 	}
 
 	return &dataobjects.Matrix{
-		Rows: rowPerSlice * params.ECCLength,
-		Cols: params.N,
-		Data: encoded,
+		Rows:    rowPerSlice * params.ECCLength,
+		Cols:    params.N,
+		Data:    encoded,
+		Layout:  dataobjects.SlicedRowMajor,
+		Blocks:  params.ECCLength,
+		Modulus: params.P,
 	}
 }
 
@@ -182,14 +206,30 @@ func (lpn *LpnMVP) Answer(encodedMatrix *dataobjects.Matrix, clientQuery *LpnQue
 
 	rowPerSlice := params.M / params.M_1
 	entryPerSlice := rowPerSlice * params.N
+	encodedMatrix.AssertLayout(params.encodedLayout())
 
 	answers := dataobjects.AlignedMake[uint32](uint64(rowPerSlice * params.ECCLength))
 
-	for i := uint32(0); i < params.ECCLength; i++ {
-		MatVecProduct(encodedMatrix.Data[i*entryPerSlice:(i+1)*entryPerSlice],
-			clientQuery.Vec[i*clientQuery.QueryLen:(i+1)*clientQuery.QueryLen],
-			answers[i*rowPerSlice:(i+1)*rowPerSlice],
-			rowPerSlice, params.N, params.P)
+	if params.Workers <= 1 {
+		for i := uint32(0); i < params.ECCLength; i++ {
+			MatVecProduct(encodedMatrix.Data[i*entryPerSlice:(i+1)*entryPerSlice],
+				clientQuery.Vec[i*clientQuery.QueryLen:(i+1)*clientQuery.QueryLen],
+				answers[i*rowPerSlice:(i+1)*rowPerSlice],
+				rowPerSlice, params.N, params.P)
+		}
+	} else {
+		// The slices are stacked, so row r of the whole matrix is row r % rowPerSlice of slice r / rowPerSlice
+		parallelRows(params.Workers, rowPerSlice*params.ECCLength, func(start, end uint32) {
+			for start < end {
+				i := start / rowPerSlice
+				stop := min(end, (i+1)*rowPerSlice)
+				MatVecProduct(encodedMatrix.Data[uint64(start)*uint64(params.N):],
+					clientQuery.Vec[i*clientQuery.QueryLen:(i+1)*clientQuery.QueryLen],
+					answers[start:stop],
+					stop-start, params.N, params.P)
+				start = stop
+			}
+		})
 	}
 
 	return &LpnResponse{
@@ -206,22 +246,25 @@ func (lpn *LpnMVP) Decode(sk SecretKey, response *LpnResponse, aux *LpnAux) []ui
 
 	result := dataobjects.AlignedMake[uint32](uint64(params.M))
 
-	code := dataobjects.AlignedMake[uint32](uint64(params.ECCLength))
-
-	ecccode := ecc.GetECCCode(ecc.ECCConfig{Name: params.ECCName, Q: params.P, N: params.ECCLength, K: params.M_1})
-
+	// Column i of the answer slices is one codeword, all columns share the noisy slices
+	codes := make([][]uint32, response.AnsLen)
+	symbols := dataobjects.AlignedMake[uint32](uint64(response.AnsLen * params.ECCLength))
 	for i := uint32(0); i < response.AnsLen; i++ {
+		codes[i] = symbols[i*params.ECCLength : (i+1)*params.ECCLength]
 		for j := uint32(0); j < params.ECCLength; j++ {
-			code[j] = response.Answers[j*response.AnsLen+i]
+			codes[i][j] = response.Answers[j*response.AnsLen+i]
 		}
+	}
 
-		message, err := ecccode.Decode(code, aux.NoisyQueryIndicator)
+	ecccode := ecc.GetECCCode(params.eccConfig())
 
-		if err != nil {
-			panic(err)
-		}
+	messages, err := ecccode.DecodeBatch(codes, aux.NoisyQueryIndicator)
+	if err != nil {
+		panic(err)
+	}
 
-		copy(result[i*params.M_1:(i+1)*params.M_1], message)
+	for i := range messages {
+		copy(result[uint32(i)*params.M_1:(uint32(i)+1)*params.M_1], messages[i])
 	}
 
 	return result
@@ -1,3 +1,5 @@
+//go:build cgo && !purego
+
 // transform.cpp
 #include <cstdint>
 #include <cstring>
@@ -25,3 +27,55 @@ void TransformRowMajorToBlockRowMajor(
         }
     }
 }
+
+void TransformRowMajorToBlockRowMajor64(
+    const uint64_t* mat,        // input: size n × m, row-major
+    uint64_t* matBlocked,       // output: size n × m, block-row-major
+    uint32_t n, uint32_t m, uint32_t s
+) {
+    assert(m % s == 0);
+    uint32_t b = m / s;
+
+    for (uint32_t row = 0; row < n; ++row) {
+        for (uint32_t blk = 0; blk < s; ++blk) {
+            for (uint32_t j = 0; j < b; ++j) {
+                uint32_t orig_col = blk * b + j;
+                size_t dest_idx = ((size_t(blk) * n + row) * b) + j;
+                matBlocked[dest_idx] = mat[size_t(row) * m + orig_col];
+            }
+        }
+    }
+}
+
+// Inverse of TransformRowMajorToBlockRowMajor
+void TransformBlockRowMajorToRowMajor(
+    const uint32_t* matBlocked, // input: size n × m, block-row-major
+    uint32_t* mat,              // output: size n × m, row-major
+    uint32_t n, uint32_t m, uint32_t s
+) {
+    assert(m % s == 0);
+    uint32_t b = m / s;
+
+    for (uint32_t blk = 0; blk < s; ++blk) {
+        for (uint32_t row = 0; row < n; ++row) {
+            const uint32_t* src = matBlocked + (size_t(blk) * n + row) * b;
+            std::memcpy(mat + size_t(row) * m + size_t(blk) * b, src, size_t(b) * sizeof(uint32_t));
+        }
+    }
+}
+
+void TransformBlockRowMajorToRowMajor64(
+    const uint64_t* matBlocked, // input: size n × m, block-row-major
+    uint64_t* mat,              // output: size n × m, row-major
+    uint32_t n, uint32_t m, uint32_t s
+) {
+    assert(m % s == 0);
+    uint32_t b = m / s;
+
+    for (uint32_t blk = 0; blk < s; ++blk) {
+        for (uint32_t row = 0; row < n; ++row) {
+            const uint64_t* src = matBlocked + (size_t(blk) * n + row) * b;
+            std::memcpy(mat + size_t(row) * m + size_t(blk) * b, src, size_t(b) * sizeof(uint64_t));
+        }
+    }
+}
@@ -12,6 +12,24 @@ void TransformRowMajorToBlockRowMajor(
     uint32_t n, uint32_t m, uint32_t s
 );
 
+void TransformRowMajorToBlockRowMajor64(
+    const uint64_t* mat,
+    uint64_t* matBlocked,
+    uint32_t n, uint32_t m, uint32_t s
+);
+
+void TransformBlockRowMajorToRowMajor(
+    const uint32_t* matBlocked,
+    uint32_t* mat,
+    uint32_t n, uint32_t m, uint32_t s
+);
+
+void TransformBlockRowMajorToRowMajor64(
+    const uint64_t* matBlocked,
+    uint64_t* mat,
+    uint32_t n, uint32_t m, uint32_t s
+);
+
 #ifdef __cplusplus
 }
 #endif
@@ -1,3 +1,5 @@
+//go:build cgo && !purego
+
 #include <iostream>
 #include <cstdint>
 #include <vector>
@@ -149,6 +151,56 @@ void BlockMatVecProduct(const uint32_t* __restrict__ mat,    // matrix: size n 
     }
 }
 
+// q vectors against a block-wise matrix, each row is read once and multiplied with every vector while it is
+// in cache. vecs holds the q vectors of length m back to back, result the q outputs of BlockMatVecProduct.
+void BlockMatMatProduct(const uint32_t* __restrict__ mat,    // matrix: size n × m, block-wise, row-major
+    const uint32_t* __restrict__ vecs,   // vectors: q × m
+    uint32_t*       __restrict__ result, // output: q × (s × n)
+    uint32_t n, uint32_t m,
+    uint32_t s, uint32_t q, uint32_t p
+) {
+    assert(m % s == 0);
+    uint32_t b = m / s;  // columns per block
+
+    for (uint32_t blk = 0; blk < s; ++blk) {
+        const uint32_t* mat_blk = mat + size_t(blk) * n * b;
+
+        for (uint32_t row = 0; row < n; ++row) {
+            const uint32_t* row_ptr = mat_blk + size_t(row) * b;
+
+            // four vectors per pass over the row
+            uint32_t v = 0;
+            for (; v + 4 <= q; v += 4) {
+                const uint32_t* vec0 = vecs + size_t(v) * m + size_t(blk) * b;
+                const uint32_t* vec1 = vec0 + m;
+                const uint32_t* vec2 = vec1 + m;
+                const uint32_t* vec3 = vec2 + m;
+
+                uint64_t acc0 = 0, acc1 = 0, acc2 = 0, acc3 = 0;
+                for (uint32_t j = 0; j < b; ++j) {
+                    uint64_t x = row_ptr[j];
+                    acc0 += x * vec0[j];
+                    acc1 += x * vec1[j];
+                    acc2 += x * vec2[j];
+                    acc3 += x * vec3[j];
+                }
+                result[size_t(v) * s * n + size_t(blk) * n + row] = uint32_t(acc0 % p);
+                result[size_t(v + 1) * s * n + size_t(blk) * n + row] = uint32_t(acc1 % p);
+                result[size_t(v + 2) * s * n + size_t(blk) * n + row] = uint32_t(acc2 % p);
+                result[size_t(v + 3) * s * n + size_t(blk) * n + row] = uint32_t(acc3 % p);
+            }
+            for (; v < q; ++v) {
+                const uint32_t* vec_blk = vecs + size_t(v) * m + size_t(blk) * b;
+
+                uint64_t acc = 0;
+                for (uint32_t j = 0; j < b; ++j) {
+                    acc += uint64_t(row_ptr[j]) * vec_blk[j];
+                }
+                result[size_t(v) * s * n + size_t(blk) * n + row] = uint32_t(acc % p);
+            }
+        }
+    }
+}
 
 // M x v
 void MatVecProduct(const uint32_t* mat, const uint32_t* vec, uint32_t* result, uint32_t n, uint32_t m, uint32_t p)
@@ -199,4 +251,94 @@ void BlockVecMatProduct(const uint32_t* mat, const uint32_t* vec, uint32_t* resu
     }
 }
 
+
+// -----------------------------------------------------------------------------
+// 64-bit variants: entries are reduced mod p < 2^64 and products are accumulated
+// in 128 bits. The accumulator is reduced every SafeTerms64(p) terms so that it
+// never overflows, e.g. every 255 terms for a 60-bit prime.
+// -----------------------------------------------------------------------------
+
+typedef unsigned __int128 uint128_t;
+
+static uint32_t SafeTerms64(uint64_t p)
+{
+    // (p-1)^2 < 2^(2L), so 2^(128-2L) - 1 products fit on top of an accumulator < p
+    int L = 64 - __builtin_clzll(p - 1 | 1);
+    if (2 * L >= 127) return 1;
+    int room = 128 - 2 * L;
+    if (room > 31) return 1u << 31;
+    return (1u << room) - 1;
+}
+
+void MatVecProduct64(const uint64_t* mat, const uint64_t* vec, uint64_t* result, uint32_t n, uint32_t m, uint64_t p)
+{
+    const uint32_t chunk = SafeTerms64(p);
+
+    for (uint32_t row = 0; row < n; ++row) {
+        const uint64_t* row_ptr = mat + size_t(row) * m;
+
+        uint128_t acc = 0;
+        uint32_t terms = 0;
+        for (uint32_t col = 0; col < m; ++col) {
+            acc += uint128_t(row_ptr[col]) * vec[col];
+            if (++terms == chunk) {
+                acc %= p;
+                terms = 0;
+            }
+        }
+
+        result[row] = uint64_t(acc % p);
+    }
+}
+
+// If the matrix are already stored by blocks, see TransformRowMajorToBlockRowMajor64.
+void BlockMatVecProduct64(const uint64_t* mat, const uint64_t* vec, uint64_t* result, uint32_t n, uint32_t m, uint32_t s, uint64_t p)
+{
+    assert(m % s == 0);
+    uint32_t b = m / s;  // columns per block
+
+    for (uint32_t blk = 0; blk < s; ++blk) {
+        const uint64_t* mat_blk = mat + size_t(blk) * n * b;
+        const uint64_t* vec_blk = vec + size_t(blk) * b;
+        uint64_t* result_blk = result + size_t(blk) * n;
+
+        MatVecProduct64(mat_blk, vec_blk, result_blk, n, b, p);
+    }
+}
+
+void BlockVecMatProduct64(const uint64_t* mat, const uint64_t* vec, uint64_t* result, uint32_t n, uint32_t m, uint32_t s, uint64_t p)
+{
+    const uint32_t chunk = SafeTerms64(p);
+    uint32_t b = n / s;
+
+    std::vector<uint128_t> acc(m);
+
+    for (uint32_t blk = 0; blk < s; ++blk) {
+        uint32_t row_start = blk * b;
+        uint64_t* res_ptr  = result + blk * size_t(m);
+
+        std::fill(acc.begin(), acc.end(), 0);
+
+        uint32_t terms = 0;
+        for (uint32_t i = 0; i < b; ++i) {
+            uint32_t row = row_start + i;
+            uint64_t v   = vec[row];
+            const uint64_t* row_ptr = mat + size_t(row) * m;
+
+            for (uint32_t col = 0; col < m; ++col) {
+                acc[col] += uint128_t(row_ptr[col]) * v;
+            }
+
+            if (++terms == chunk) {
+                for (uint32_t col = 0; col < m; ++col) acc[col] %= p;
+                terms = 0;
+            }
+        }
+
+        for (uint32_t col = 0; col < m; ++col) {
+            res_ptr[col] = uint64_t(acc[col] % p);
+        }
+    }
+}
+
 }
\ No newline at end of file
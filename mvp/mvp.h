@@ -9,10 +9,18 @@ extern "C" {
 
 void BlockMatVecProduct(const uint32_t* mat, const uint32_t* vec, uint32_t* result, uint32_t n, uint32_t m, uint32_t s, uint32_t p);
 
+void BlockMatMatProduct(const uint32_t* mat, const uint32_t* vecs, uint32_t* result, uint32_t n, uint32_t m, uint32_t s, uint32_t q, uint32_t p);
+
 void MatVecProduct(const uint32_t* mat, const uint32_t* vec, uint32_t* result, uint32_t n, uint32_t m, uint32_t p);
 
 void BlockVecMatProduct(const uint32_t* mat, const uint32_t* vec, uint32_t* result, uint32_t n, uint32_t m, uint32_t s, uint32_t p);
 
+void BlockMatVecProduct64(const uint64_t* mat, const uint64_t* vec, uint64_t* result, uint32_t n, uint32_t m, uint32_t s, uint64_t p);
+
+void MatVecProduct64(const uint64_t* mat, const uint64_t* vec, uint64_t* result, uint32_t n, uint32_t m, uint64_t p);
+
+void BlockVecMatProduct64(const uint64_t* mat, const uint64_t* vec, uint64_t* result, uint32_t n, uint32_t m, uint32_t s, uint64_t p);
+
 #ifdef __cplusplus
 }
 #endif
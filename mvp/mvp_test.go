@@ -4,9 +4,12 @@ import (
 	"RandomLinearCodePIR/dataobjects"
 	"RandomLinearCodePIR/ecc"
 	"RandomLinearCodePIR/linearcode"
+	"RandomLinearCodePIR/tdm"
 	"RandomLinearCodePIR/utils"
 	"fmt"
 	"math"
+	"math/big"
+	"runtime"
 	"testing"
 	"time"
 )
@@ -104,6 +107,10 @@ func TestLPNMVPComplete(t *testing.T) {
 		ECCName:   ecc.ReedSolomon,
 	}}
 
+	if failure := pi.Params.DecodingFailureProbability(); failure > 1e-20 {
+		t.Fatalf("decoding fails with probability %g", failure)
+	}
+
 	matrix := utils.GeneratePrimeFieldMatrix(pi.Params.M, pi.Params.L, p, seed)
 
 	fmt.Printf("\n\nRunning LPN Variant MVP with Database %d * %d \n", pi.Params.M, pi.Params.L)
@@ -150,6 +157,82 @@ func TestLPNMVPComplete(t *testing.T) {
 	}
 }
 
+// checkLpnMVP runs the LpnMVP flow with the code eccName, lets damage alter the answer and checks the decoded product
+func checkLpnMVP(t *testing.T, eccName string, m1, eccLength uint32, damage func(response *LpnResponse, aux *LpnAux)) {
+	m := uint32(1 << 8)
+	l := uint32(1 << 8)
+	k := uint32(1 << 4)
+	p := uint32(65537)
+	seed := int64(1)
+
+	pi := &LpnMVP{Params: LpnParams{
+		Field:     dataobjects.NewPrimeField(p),
+		K:         k,
+		N:         k + l,
+		M:         m,
+		L:         l,
+		M_1:       m1,
+		ECCLength: eccLength,
+		Epsi:      math.Pow(2, -40),
+		P:         p,
+		ECCName:   eccName,
+	}}
+
+	matrix := utils.GeneratePrimeFieldMatrix(m, l, p, seed)
+	query := utils.RandomPrimeFieldVector(l, p)
+
+	sk := pi.KeyGen(seed)
+	encodedMatrix := pi.Encode(sk, matrix, pi.GenerateTDM(sk))
+	clientQuery, aux := pi.Query(sk, query)
+	response := pi.Answer(encodedMatrix, clientQuery)
+	damage(response, aux)
+	val := pi.Decode(sk, response, aux)
+
+	target := dataobjects.AlignedMake[uint32](uint64(m))
+	MatVecProduct(matrix.Data, query, target, m, l, p)
+	for i := range target {
+		if target[i] != val[i] {
+			t.Fatalf("%s: entry %d is %d, expected %d", eccName, i, val[i], target[i])
+		}
+	}
+}
+
+// Test LpnMVP with each erasure code, with erased slices among the systematic ones, and the errors-and-erasures
+// decoder with a slice corrupted at a position the client does not know
+func TestLPNMVPErasureCodes(t *testing.T) {
+	field := dataobjects.NewPrimeField(65537)
+	erase := func(response *LpnResponse, aux *LpnAux) {
+		for _, erased := range []uint32{0, 3, 7, 20, 41} {
+			aux.NoisyQueryIndicator[erased] = true
+			for i := uint32(0); i < response.AnsLen; i++ {
+				response.Answers[erased*response.AnsLen+i] = 0
+			}
+		}
+	}
+	// Slice 1 carries message symbols, so the systematic shortcut of the erasure decoder would return it as is
+	corrupt := func(response *LpnResponse, aux *LpnAux) {
+		for i := uint32(0); i < response.AnsLen; i++ {
+			idx := response.AnsLen + i
+			response.Answers[idx] = field.Add(response.Answers[idx], 1)
+		}
+	}
+
+	for _, test := range []struct {
+		name      string
+		m1        uint32
+		eccLength uint32
+		damage    func(response *LpnResponse, aux *LpnAux)
+	}{
+		{ecc.ReedSolomonGao, 4, 12, corrupt},
+		// An ECCLength the dense generator would make slow
+		{ecc.NTTReedSolomon, 8, 64, erase},
+		{ecc.RandomLinear, 8, 64, erase},
+		{ecc.LDPC, 8, 64, erase},
+	} {
+		checkLpnMVP(t, test.name, test.m1, test.eccLength, test.damage)
+	}
+}
+
 // Test full flow correctness of Ring variant of Split-LSN MVP
 func TestRingSlsnMVPComplete(t *testing.T) {
 	m := uint32(1 << 10)
@@ -232,6 +315,181 @@ func TestRingSlsnMVPComplete(t *testing.T) {
 	}
 }
 
+// checkSlsnMVPOverField runs the full flow of the Split-LSN scheme name over field and the linear code named code
+// with single, parallel and batched answers
+func checkSlsnMVPOverField(t *testing.T, name string, field dataobjects.Field, code string) {
+	m, l, k := uint32(1<<6), uint32(1<<6), uint32(1<<4)
+	scheme, err := NewScheme(SchemeConfig{Name: name, Field: field, M: m, L: l, K: k, S: 2, LinearCode: code, Workers: 2})
+	if err != nil {
+		t.Fatalf("%s over %T: %v", name, field, err)
+	}
+	var pi interface {
+		KeyGen(seed int64) SecretKey
+		GenerateTDM(sk SecretKey) []uint32
+		Encode(sk SecretKey, input dataobjects.Matrix, mask []uint32) *dataobjects.Matrix
+		Query(sk SecretKey, vec []uint32) (*SlsnQuery, *SlsnAux)
+		Answer(encodedMatrix dataobjects.Matrix, clientQuery SlsnQuery) []uint32
+		Decode(sk SecretKey, response []uint32, aux SlsnAux) []uint32
+		QueryBatch(sk SecretKey, vecs [][]uint32) (*SlsnBatchQuery, []SlsnAux)
+		AnswerBatch(encodedMatrix dataobjects.Matrix, clientQuery SlsnBatchQuery) []uint32
+		DecodeBatch(sk SecretKey, response []uint32, auxes []SlsnAux) [][]uint32
+	}
+	switch scheme := scheme.(type) {
+	case slsnScheme:
+		pi = scheme.SlsnMVP
+	case ringSlsnScheme:
+		pi = scheme.RingSlsnMVP
+	}
+
+	matrix := dataobjects.Matrix{Rows: m, Cols: l, Data: field.SampleVector(m * l)}
+	vecs := [][]uint32{field.SampleVector(l), field.SampleVector(l)}
+	targets := make([][]uint32, len(vecs))
+	for v, vec := range vecs {
+		targets[v] = make([]uint32, m)
+		for i := uint32(0); i < m; i++ {
+			for j := uint32(0); j < l; j++ {
+				targets[v][i] = field.Add(targets[v][i], field.Mul(matrix.Data[i*l+j], vec[j]))
+			}
+		}
+	}
+
+	sk := pi.KeyGen(1)
+	encoded := pi.Encode(sk, matrix, pi.GenerateTDM(sk))
+	clientQuery, aux := pi.Query(sk, vecs[0])
+	val := pi.Decode(sk, pi.Answer(*encoded, *clientQuery), *aux)
+	for i := range val {
+		if val[i] != targets[0][i] {
+			t.Fatalf("%s over %T: wrong answer at %d", name, field, i)
+		}
+	}
+
+	batch, auxes := pi.QueryBatch(sk, vecs)
+	for v, val := range pi.DecodeBatch(sk, pi.AnswerBatch(*encoded, *batch), auxes) {
+		for i := range val {
+			if val[i] != targets[v][i] {
+				t.Fatalf("%s over %T: wrong batched answer %d at %d", name, field, v, i)
+			}
+		}
+	}
+}
+
+// Test SlsnMVP and RingSlsnMVP over Z_2^k, including k = 32 whose Mod is 0
+func TestSlsnMVPRingZ2k(t *testing.T) {
+	for _, k := range []uint32{32, 13} {
+		checkSlsnMVPOverField(t, SlsnScheme, dataobjects.NewRingZ2k(k), "")
+		checkSlsnMVPOverField(t, RingSlsnScheme, dataobjects.NewRingZ2k(k), "")
+	}
+
+	for _, config := range []SchemeConfig{
+		{Name: SlsnScheme, Field: dataobjects.NewRingZ2k(32), M: 64, L: 64, K: 16, S: 2, LinearCode: linearcode.Vandermonde},
+		{Name: RingSlsnScheme, Field: dataobjects.NewRingZ2k(32), M: 64, L: 64, K: 16, S: 2, LinearCode: linearcode.StreamingRandom},
+		{Name: LpnScheme, Field: dataobjects.NewRingZ2k(32), P: 65537, M: 64, L: 64, K: 16, M_1: 4, ECCLength: 7, ECCName: ecc.ReedSolomon},
+	} {
+		if _, err := NewScheme(config); err == nil {
+			t.Fatalf("%s built over Z_2^32", config.Name)
+		}
+	}
+}
+
+// Test SlsnMVP and RingSlsnMVP over GF(2^k), whose products the native prime kernels would get wrong
+func TestSlsnMVPBinaryField(t *testing.T) {
+	for _, k := range []uint32{8, 32} {
+		checkSlsnMVPOverField(t, SlsnScheme, dataobjects.NewBinaryField(k), "")
+		checkSlsnMVPOverField(t, RingSlsnScheme, dataobjects.NewBinaryField(k), "")
+	}
+
+	for _, config := range []SchemeConfig{
+		{Name: SlsnScheme, Field: dataobjects.NewExtensionField(17, 2), P: 17, M: 64, L: 64, K: 16, S: 2},
+		{Name: RingSlsnScheme, Field: dataobjects.NewExtensionField(17, 2), P: 17, M: 64, L: 64, K: 16, S: 2},
+	} {
+		if _, err := NewScheme(config); err == nil {
+			t.Fatalf("%s built over %T", config.Name, config.Field)
+		}
+	}
+}
+
+// Test the 64-bit kernels against math/big for a 60-bit NTT-friendly prime
+func TestMVP64(t *testing.T) {
+	p := uint64(1152921504606584833) // 2^60 - 2^18 + 1
+	field := dataobjects.NewPrimeField64(p)
+	n := uint32(64)
+	m := uint32(1024)
+	s := uint32(4)
+
+	mat := field.SampleVector(n * m)
+	vec := field.SampleVector(m)
+	P := new(big.Int).SetUint64(p)
+
+	// expected[blk*n + row] = <row of block blk, vec of block blk>
+	b := m / s
+	expected := make([]uint64, s*n)
+	for blk := uint32(0); blk < s; blk++ {
+		for row := uint32(0); row < n; row++ {
+			acc := new(big.Int)
+			for j := blk * b; j < (blk+1)*b; j++ {
+				term := new(big.Int).SetUint64(mat[row*m+j])
+				acc.Add(acc, term.Mul(term, new(big.Int).SetUint64(vec[j])))
+			}
+			expected[blk*n+row] = acc.Mod(acc, P).Uint64()
+		}
+	}
+
+	blocked := dataobjects.AlignedMake[uint64](uint64(n * m))
+	TransformToBlockwise64(mat, blocked, n, m, s)
+	out := dataobjects.AlignedMake[uint64](uint64(s * n))
+	BlockMatVecProduct64(blocked, vec, out, n, m, s, p)
+	for i := range out {
+		if out[i] != expected[i] {
+			t.Fatalf("BlockMatVecProduct64 mismatch at %d", i)
+		}
+	}
+
+	full := dataobjects.AlignedMake[uint64](uint64(n))
+	MatVecProduct64(mat, vec, full, n, m, p)
+	for row := uint32(0); row < n; row++ {
+		acc := uint64(0)
+		for blk := uint32(0); blk < s; blk++ {
+			acc = field.Add(acc, expected[blk*n+row])
+		}
+		if full[row] != acc {
+			t.Fatalf("MatVecProduct64 mismatch at row %d", row)
+		}
+	}
+
+	// Transposed product, block blk of the result is vec[blk rows] * mat[blk rows]
+	left := field.SampleVector(n)
+	tr := dataobjects.AlignedMake[uint64](uint64(2 * m))
+	BlockVecMatProduct64(mat, left, tr, n, m, 2, p)
+	for col := uint32(0); col < m; col += 97 {
+		for blk := uint32(0); blk < 2; blk++ {
+			acc := uint64(0)
+			for row := blk * n / 2; row < (blk+1)*n/2; row++ {
+				acc = field.Add(acc, field.Mul(left[row], mat[row*m+col]))
+			}
+			if tr[blk*m+col] != acc {
+				t.Fatalf("BlockVecMatProduct64 mismatch at block %d column %d", blk, col)
+			}
+		}
+	}
+
+	// Cyclic convolution through the 64-bit NTT
+	degree := uint32(256)
+	x := field.SampleVector(degree)
+	y := field.SampleVector(degree)
+	conv := dataobjects.AlignedMake[uint64](uint64(degree))
+	root := tdm.NthRootOfUnity64(p, uint64(degree))
+	tdm.NTT_Convolution64(x, y, conv, degree, root, p)
+	for i := uint32(0); i < degree; i += 31 {
+		acc := uint64(0)
+		for j := uint32(0); j < degree; j++ {
+			acc = field.Add(acc, field.Mul(x[j], y[(degree+i-j)%degree]))
+		}
+		if conv[i] != acc {
+			t.Fatalf("NTT_Convolution64 mismatch at %d", i)
+		}
+	}
+}
+
 // Benchmark cleartext server execution time for matrix-vector product
 func BenchmarkCleartextServerExecution(b *testing.B) {
 	printTestName("Benchmark ClearText")
@@ -447,6 +705,72 @@ func BenchmarkSLSNEncoding(b *testing.B) {
 	fmt.Printf("Average Encoding time: %s\n", totalDuration/time.Duration(b.N))
 }
 
+// Benchmark answering 16 queries at once against answering them one by one
+func BenchmarkSLSNAnswerBatch(b *testing.B) {
+	printTestName("Benchmark SLSN Answer Batch")
+	n, m, l, k, s, block := getParams()
+	p := uint32(65537)
+	count := uint32(16)
+
+	pi := &SlsnMVP{Params: SlsnParams{Field: dataobjects.NewPrimeField(p), S: s, K: k, N: n, M: m, L: l, B: block, P: p}}
+	encoded := dataobjects.Matrix{Rows: m, Cols: n, Data: utils.RandomPrimeFieldVector(m*n, p),
+		Layout: dataobjects.BlockRowMajor, Blocks: s, Modulus: p}
+	batch := SlsnBatchQuery{Vecs: utils.RandomPrimeFieldVector(count*n, p), Count: count}
+
+	var batchDuration, singleDuration time.Duration
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		start := time.Now()
+		pi.AnswerBatch(encoded, batch)
+		batchDuration += time.Since(start)
+
+		start = time.Now()
+		for j := uint32(0); j < count; j++ {
+			pi.Answer(encoded, SlsnQuery{Vec: batch.Vecs[j*n : (j+1)*n]})
+		}
+		singleDuration += time.Since(start)
+	}
+
+	b.StopTimer()
+	fmt.Printf("Benchmark of %d SLSN Answers for %d x %d encoded DB\n", count, m, n)
+	printBenchmarkExecutionTime(b.N)
+	fmt.Printf("Average time batched: %s, one by one: %s\n", batchDuration/time.Duration(b.N), singleDuration/time.Duration(b.N))
+}
+
+// Benchmark Answer on one worker per CPU against the serial Answer
+func BenchmarkSLSNAnswerParallel(b *testing.B) {
+	printTestName("Benchmark SLSN Answer Parallel")
+	n, m, l, k, s, block := getParams()
+	p := uint32(65537)
+	workers := uint32(runtime.NumCPU())
+
+	pi := &SlsnMVP{Params: SlsnParams{Field: dataobjects.NewPrimeField(p), S: s, K: k, N: n, M: m, L: l, B: block, P: p}}
+	encoded := dataobjects.Matrix{Rows: m, Cols: n, Data: utils.RandomPrimeFieldVector(m*n, p),
+		Layout: dataobjects.BlockRowMajor, Blocks: s, Modulus: p}
+	query := SlsnQuery{Vec: utils.RandomPrimeFieldVector(n, p)}
+
+	var parallelDuration, serialDuration time.Duration
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		pi.Params.Workers = workers
+		start := time.Now()
+		pi.Answer(encoded, query)
+		parallelDuration += time.Since(start)
+
+		pi.Params.Workers = 0
+		start = time.Now()
+		pi.Answer(encoded, query)
+		serialDuration += time.Since(start)
+	}
+
+	b.StopTimer()
+	fmt.Printf("Benchmark of SLSN Answer for %d x %d encoded DB\n", m, n)
+	printBenchmarkExecutionTime(b.N)
+	fmt.Printf("Average time on %d workers: %s, serial: %s\n", workers, parallelDuration/time.Duration(b.N), serialDuration/time.Duration(b.N))
+}
+
 // Benchmark query generation in Split-LSN MVP
 func BenchmarkSLSNQuery(b *testing.B) {
 	printTestName("Benchmark SLSN Query")
@@ -510,6 +834,9 @@ func BenchmarkSLSNAnswer(b *testing.B) {
 	}}
 
 	encodedMatrix := utils.GeneratePrimeFieldMatrix(pi.Params.M, pi.Params.N, p, seed)
+	// Random entries, so relabelling is enough to get the layout Answer expects
+	encodedMatrix.Layout = dataobjects.BlockRowMajor
+	encodedMatrix.Blocks = s
 
 	var totalDuration time.Duration
 
@@ -587,3 +914,326 @@ func printTestName(name string) {
 func printBenchmarkExecutionTime(n int) {
 	fmt.Printf("Benchmark Execution For *** %d *** Times \n", n)
 }
+
+// Test that TransformFromBlockwise undoes TransformToBlockwise and that Answer rejects a row-major database
+func TestAnswerLayout(t *testing.T) {
+	p := uint32(65537)
+	m, n, s := uint32(16), uint32(64), uint32(4)
+	mat := utils.GeneratePrimeFieldMatrix(m, n, p, 1)
+	blocked := dataobjects.AlignedMake[uint32](uint64(m * n))
+	back := dataobjects.AlignedMake[uint32](uint64(m * n))
+	TransformToBlockwise(mat.Data, blocked, m, n, s)
+	TransformFromBlockwise(blocked, back, m, n, s)
+	for i := range back {
+		if back[i] != mat.Data[i] {
+			t.Fatalf("round trip mismatch at %d", i)
+		}
+	}
+
+	pi := &SlsnMVP{Params: SlsnParams{Field: dataobjects.NewPrimeField(p), P: p, S: s, B: n / s, M: m, N: n}}
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Answer accepted a row-major matrix")
+		}
+	}()
+	pi.Answer(mat, SlsnQuery{Vec: utils.RandomPrimeFieldVector(n, p)})
+}
+
+// Test that an encoded database survives saving, loading and mapping, and that other params are refused
+func TestSaveLoadEncoded(t *testing.T) {
+	m, l, k, s := uint32(64), uint32(64), uint32(16), uint32(2)
+	p := uint32(65537)
+	params := SlsnParams{Field: dataobjects.NewPrimeField(p), P: p, S: s, K: k, L: l, N: k + l, M: m, B: (k + l) / s}
+	pi := &SlsnMVP{Params: params}
+
+	sk := pi.KeyGen(1)
+	encoded := pi.Encode(sk, utils.GeneratePrimeFieldMatrix(m, l, p, 1), pi.GenerateTDM(sk))
+	path := t.TempDir() + "/slsn.db"
+	if err := pi.SaveEncoded(path, encoded); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	loaded, err := (&SlsnMVP{Params: params}).LoadEncoded(path)
+	if err != nil || !loaded.Equal(encoded) {
+		t.Fatalf("load: %v", err)
+	}
+	query, aux := pi.Query(sk, utils.RandomPrimeFieldVector(l, p))
+	a, b := pi.Decode(sk, pi.Answer(*encoded, *query), *aux), pi.Decode(sk, pi.Answer(*loaded, *query), *aux)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("answers differ at %d", i)
+		}
+	}
+
+	mapped, err := (&SlsnMVP{Params: params}).MapEncoded(path)
+	if err != nil {
+		t.Fatalf("map: %v", err)
+	}
+	c := pi.Decode(sk, pi.Answer(mapped.Matrix, *query), *aux)
+	for i := range a {
+		if a[i] != c[i] {
+			t.Fatalf("answers over the mapped file differ at %d", i)
+		}
+	}
+	mapped.Close()
+
+	other := params
+	other.K, other.L = k*2, l-k
+	if _, err := (&SlsnMVP{Params: other}).LoadEncoded(path); err == nil {
+		t.Fatalf("database loaded with other params")
+	}
+	if _, err := (&LpnMVP{Params: LpnParams{Field: params.Field, P: p, N: k + l, M: m, M_1: 1, ECCLength: 1}}).LoadEncoded(path); err == nil {
+		t.Fatalf("SlsnMVP database loaded as LpnMVP")
+	}
+
+	// GF(2^8) with another polynomial has the same P
+	aes, other8 := dataobjects.NewBinaryField(8), dataobjects.NewBinaryFieldWithPoly(8, 0x11d)
+	params = SlsnParams{Field: aes, P: aes.Mod(), S: s, K: k, L: l, N: k + l, M: m, B: (k + l) / s}
+	pi = &SlsnMVP{Params: params}
+	sk = pi.KeyGen(1)
+	matrix := dataobjects.Matrix{Rows: m, Cols: l, Data: aes.SampleVector(m * l)}
+	if err := pi.SaveEncoded(path, pi.Encode(sk, matrix, pi.GenerateTDM(sk))); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, err := pi.LoadEncoded(path); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	other.Field, other.P, other.K, other.L = other8, other8.Mod(), k, l
+	if _, err := (&SlsnMVP{Params: other}).LoadEncoded(path); err == nil {
+		t.Fatalf("database loaded over another GF(2^8)")
+	}
+}
+
+// Test SlsnMVP over a linear code chosen through its params
+func TestSlsnMVPLinearCode(t *testing.T) {
+	m, l, k := uint32(1<<6), uint32(1<<6), uint32(1<<3)
+	p := uint32(65537)
+	field := dataobjects.NewPrimeField(p)
+
+	code := linearcode.GetLinearCode(linearcode.LinearCodeConfig{Name: linearcode.Random, K: k, L: l, Field: field, Seed: 3})
+	r, x := utils.RandomPrimeFieldVector(k, p), utils.RandomPrimeFieldVector(l, p)
+	lsn, dual := code.EncodeLSN(r), code.EncodeDual(x)
+	dualMatrix := linearcode.Generate1DDualMatrix(l, k, field, 3)
+	rlcMatrix := linearcode.Generate1DRLCMatrix(l, k, field, 3)
+	for i := uint32(0); i < l; i++ {
+		sum := uint32(0)
+		for j := uint32(0); j < k; j++ {
+			sum = field.Add(sum, field.Mul(dualMatrix[i*k+j], r[j]))
+		}
+		if sum != lsn[i] {
+			t.Fatalf("EncodeLSN differs from the dual matrix at %d", i)
+		}
+	}
+	for j := uint32(0); j < k; j++ {
+		sum := uint32(0)
+		for i := uint32(0); i < l; i++ {
+			sum = field.Add(sum, field.Mul(rlcMatrix[j*l+i], x[i]))
+		}
+		if sum != dual[j] {
+			t.Fatalf("EncodeDual differs from the RLC matrix at %d", j)
+		}
+	}
+
+	for _, name := range []string{"", linearcode.Random, linearcode.Vandermonde, linearcode.QuasiCyclic, linearcode.StreamingRandom} {
+		pi := &SlsnMVP{Params: SlsnParams{Field: field, S: 2, K: k, N: k + l, M: m, L: l, B: (k + l) / 2, P: p, LinearCode: name}}
+		matrix := utils.GeneratePrimeFieldMatrix(m, l, p, 1)
+		query := utils.RandomPrimeFieldVector(l, p)
+		sk := pi.KeyGen(1)
+		if (sk.LinearCode == nil) == (len(sk.PreLoadedMatrix) == 0) {
+			t.Fatalf("%q: key must hold exactly one of the code and the preloaded matrix", name)
+		}
+		encoded := pi.Encode(sk, matrix, pi.GenerateTDM(sk))
+		clientQuery, aux := pi.Query(sk, query)
+		val := pi.Decode(sk, pi.Answer(*encoded, *clientQuery), *aux)
+
+		target := dataobjects.AlignedMake[uint32](uint64(m))
+		BlockMatVecProduct(matrix.Data, query, target, m, l, 1, p)
+		for i := range target {
+			if target[i] != val[i] {
+				t.Fatalf("%q: wrong answer at %d", name, i)
+			}
+		}
+	}
+}
+
+// Test SlsnMVP with the streaming random code over the fields of 2^32 elements
+func TestSlsnMVPStreamingRandomCode(t *testing.T) {
+	for _, field := range []dataobjects.Field{dataobjects.NewRingZ2k(32), dataobjects.NewBinaryField(32)} {
+		checkSlsnMVPOverField(t, SlsnScheme, field, linearcode.StreamingRandom)
+	}
+}
+
+// Test the full flow of every registered scheme through the Scheme interface
+func TestSchemeRegistry(t *testing.T) {
+	m, l, k := uint32(1<<6), uint32(1<<6), uint32(1<<4)
+	p := uint32(65537)
+	matrix := utils.GeneratePrimeFieldMatrix(m, l, p, 1)
+	vec := utils.RandomPrimeFieldVector(l, p)
+	target := dataobjects.AlignedMake[uint32](uint64(m))
+	MatVecProduct(matrix.Data, vec, target, m, l, p)
+
+	for _, name := range RegisteredSchemes() {
+		scheme, err := NewScheme(SchemeConfig{
+			Name: name, P: p, M: m, L: l, K: k,
+			S:    2,
+			Epsi: math.Pow(2, -40), M_1: 4, ECCLength: 7, ECCName: ecc.ReedSolomon,
+			Workers: 3,
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		sk := scheme.KeyGen(1)
+		encoded, err := scheme.Encode(sk, matrix, scheme.GenerateTDM(sk))
+		if err != nil {
+			t.Fatalf("%s: encode: %v", name, err)
+		}
+		path := t.TempDir() + "/db"
+		if err := scheme.SaveEncoded(path, encoded); err != nil {
+			t.Fatalf("%s: save: %v", name, err)
+		}
+		loaded, err := scheme.LoadEncoded(path)
+		if err != nil {
+			t.Fatalf("%s: load: %v", name, err)
+		}
+
+		query, aux := scheme.Query(sk, vec)
+		response, err := scheme.Answer(loaded, query)
+		if err != nil {
+			t.Fatalf("%s: answer: %v", name, err)
+		}
+		val, err := scheme.Decode(sk, response, aux)
+		if err != nil {
+			t.Fatalf("%s: decode: %v", name, err)
+		}
+		for i := range target {
+			if target[i] != val[i] {
+				t.Fatalf("%s: wrong answer at %d", name, i)
+			}
+		}
+
+		// Values of another scheme are rejected
+		if _, err := scheme.Encode(sk, matrix, [][][]uint32{}); err == nil {
+			t.Fatalf("%s: encoded with foreign masks", name)
+		}
+		if _, err := scheme.Answer(loaded, vec); err == nil {
+			t.Fatalf("%s: answered a foreign query", name)
+		}
+		if _, err := scheme.Decode(sk, response, vec); err == nil {
+			t.Fatalf("%s: decoded with a foreign aux", name)
+		}
+	}
+
+	for _, config := range []SchemeConfig{
+		{Name: "Unknown", P: p, M: m, L: l, K: k, S: 2},
+		{Name: SlsnScheme, P: p, M: m, L: l, K: k, S: 3},
+		{Name: SlsnScheme, P: p, M: m, L: l, K: k, S: 2, LinearCode: linearcode.ExtensionVandermonde},
+		{Name: RingSlsnScheme, P: p, M: m, L: l, K: k, S: 2, LinearCode: linearcode.Random},
+		{Name: LpnScheme, P: p, M: m, L: l, K: k, M_1: 3, ECCLength: 7, ECCName: ecc.ReedSolomon},
+		{Name: LpnScheme, P: p, M: m, L: l, K: k, M_1: 4, ECCLength: 7, ECCName: "Unknown"},
+		{Name: LpnScheme, P: p, M: m, L: l, K: k, M_1: 4, ECCLength: 7, ECCName: ecc.ExtensionReedSolomon},
+	} {
+		if _, err := NewScheme(config); err == nil {
+			t.Fatalf("scheme built from %+v", config)
+		}
+	}
+}
+
+// Test batched queries against single answers and the plain product
+func TestSlsnMVPBatch(t *testing.T) {
+	m, l, k := uint32(1<<6), uint32(1<<6), uint32(1<<4)
+	p := uint32(65537)
+	pi := &SlsnMVP{Params: SlsnParams{Field: dataobjects.NewPrimeField(p), S: 2, K: k, N: k + l, M: m, L: l, B: (k + l) / 2, P: p}}
+	ring := &RingSlsnMVP{SlsnMVP: *pi}
+	matrix := utils.GeneratePrimeFieldMatrix(m, l, p, 1)
+
+	vecs := make([][]uint32, 5)
+	for i := range vecs {
+		vecs[i] = utils.RandomPrimeFieldVector(l, p)
+	}
+
+	for _, scheme := range []interface {
+		KeyGen(seed int64) SecretKey
+		GenerateTDM(sk SecretKey) []uint32
+		Encode(sk SecretKey, input dataobjects.Matrix, mask []uint32) *dataobjects.Matrix
+		Answer(encodedMatrix dataobjects.Matrix, clientQuery SlsnQuery) []uint32
+		QueryBatch(sk SecretKey, vecs [][]uint32) (*SlsnBatchQuery, []SlsnAux)
+		AnswerBatch(encodedMatrix dataobjects.Matrix, clientQuery SlsnBatchQuery) []uint32
+		DecodeBatch(sk SecretKey, response []uint32, auxes []SlsnAux) [][]uint32
+	}{pi, ring} {
+		sk := scheme.KeyGen(1)
+		encoded := scheme.Encode(sk, matrix, scheme.GenerateTDM(sk))
+
+		batch, auxes := scheme.QueryBatch(sk, vecs)
+		response := scheme.AnswerBatch(*encoded, *batch)
+		vals := scheme.DecodeBatch(sk, response, auxes)
+
+		size := pi.Params.S * m
+		for i, vec := range vecs {
+			single := scheme.Answer(*encoded, SlsnQuery{Vec: batch.Vecs[uint32(i)*pi.Params.N : uint32(i+1)*pi.Params.N]})
+			for j := range single {
+				if single[j] != response[uint32(i)*size+uint32(j)] {
+					t.Fatalf("batched answer %d differs from Answer at %d", i, j)
+				}
+			}
+
+			target := dataobjects.AlignedMake[uint32](uint64(m))
+			MatVecProduct(matrix.Data, vec, target, m, l, p)
+			for j := range target {
+				if target[j] != vals[i][j] {
+					t.Fatalf("query %d: wrong answer at %d", i, j)
+				}
+			}
+		}
+	}
+}
+
+// Test that the parallel Answer gives exactly the serial answers
+func TestParallelAnswer(t *testing.T) {
+	p := uint32(65537)
+	field := dataobjects.NewPrimeField(p)
+
+	m, n, s := uint32(67), uint32(80), uint32(2)
+	slsn := &SlsnMVP{Params: SlsnParams{Field: field, S: s, K: 16, N: n, M: m, L: n - 16, B: n / s, P: p}}
+	encoded := dataobjects.Matrix{Rows: m, Cols: n, Data: utils.RandomPrimeFieldVector(m*n, p),
+		Layout: dataobjects.BlockRowMajor, Blocks: s, Modulus: p}
+	query := SlsnQuery{Vec: utils.RandomPrimeFieldVector(n, p)}
+	serial := slsn.Answer(encoded, query)
+
+	lpn := &LpnMVP{Params: LpnParams{Field: field, K: 16, N: n, M: 60, L: n - 16, M_1: 4, ECCLength: 7, P: p}}
+	rows := lpn.Params.M / lpn.Params.M_1 * lpn.Params.ECCLength
+	lpnEncoded := &dataobjects.Matrix{Rows: rows, Cols: n, Data: utils.RandomPrimeFieldVector(rows*n, p),
+		Layout: dataobjects.SlicedRowMajor, Blocks: lpn.Params.ECCLength, Modulus: p}
+	lpnQuery := &LpnQuery{Vec: utils.RandomPrimeFieldVector(n*lpn.Params.ECCLength, p), QueryLen: n, NumOfQueries: lpn.Params.ECCLength}
+	lpnSerial := lpn.Answer(lpnEncoded, lpnQuery)
+
+	batch := SlsnBatchQuery{Vecs: utils.RandomPrimeFieldVector(3*n, p), Count: 3}
+	batchSerial := slsn.AnswerBatch(encoded, batch)
+
+	for _, workers := range []uint32{2, 3, 8, 64, 200} {
+		slsn.Params.Workers = workers
+		parallel := slsn.Answer(encoded, query)
+		for i := range serial {
+			if serial[i] != parallel[i] {
+				t.Fatalf("SlsnMVP with %d workers differs at %d", workers, i)
+			}
+		}
+		batchParallel := slsn.AnswerBatch(encoded, batch)
+		for i := range batchSerial {
+			if batchSerial[i] != batchParallel[i] {
+				t.Fatalf("SlsnMVP batch with %d workers differs at %d", workers, i)
+			}
+		}
+
+		lpn.Params.Workers = workers
+		lpnParallel := lpn.Answer(lpnEncoded, lpnQuery)
+		if lpnParallel.AnsLen != lpnSerial.AnsLen {
+			t.Fatalf("LpnMVP with %d workers answers %d rows per slice", workers, lpnParallel.AnsLen)
+		}
+		for i := range lpnSerial.Answers {
+			if lpnSerial.Answers[i] != lpnParallel.Answers[i] {
+				t.Fatalf("LpnMVP with %d workers differs at %d", workers, i)
+			}
+		}
+	}
+}
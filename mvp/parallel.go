@@ -0,0 +1,23 @@
+package mvp
+
+import "sync"
+
+// parallelRows splits [0, rows) into up to workers contiguous ranges and runs f on each in its own goroutine,
+// f runs once on the whole range if workers is at most 1
+func parallelRows(workers, rows uint32, f func(start, end uint32)) {
+	if workers <= 1 || rows <= 1 {
+		f(0, rows)
+		return
+	}
+
+	chunk := (rows + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := uint32(0); start < rows; start += chunk {
+		wg.Add(1)
+		go func(start, end uint32) {
+			defer wg.Done()
+			f(start, end)
+		}(start, min(start+chunk, rows))
+	}
+	wg.Wait()
+}
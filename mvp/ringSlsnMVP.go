@@ -11,13 +11,20 @@ type RingSlsnMVP struct {
 	LinearCodeEncoder linearcode.LinearCode
 }
 
+// KeyGen uses the evaluation code over a prime field. RingZ2k and BinaryField have no evaluation points
+// to spare, there the random code streamed from seed takes its place.
 func (rmvp *RingSlsnMVP) KeyGen(seed int64) SecretKey {
-	rmvp.LinearCodeEncoder = linearcode.GetLinearCode(linearcode.LinearCodeConfig{
-		Name:  linearcode.Vandermonde,
-		K:     rmvp.SlsnMVP.Params.K,
-		L:     rmvp.SlsnMVP.Params.L,
-		Field: rmvp.SlsnMVP.Params.Field,
-	})
+	params := rmvp.SlsnMVP.Params
+	if nativeKernels(params.Field) {
+		rmvp.LinearCodeEncoder = linearcode.GetLinearCode(linearcode.LinearCodeConfig{
+			Name:  linearcode.Vandermonde,
+			K:     params.K,
+			L:     params.L,
+			Field: params.Field,
+		})
+	} else {
+		rmvp.LinearCodeEncoder = linearcode.NewStreamingRandomCode(params.K, params.L, params.Field, seed)
+	}
 	return rmvp.SlsnMVP.KeyGen(seed)
 }
 
@@ -29,9 +36,17 @@ func (rmvp *RingSlsnMVP) Encode(sk SecretKey, input dataobjects.Matrix, mask []u
 	params := rmvp.SlsnMVP.Params
 	encoded := dataobjects.AlignedMake[uint32](uint64(input.Rows * params.N))
 
-	for i := uint32(0); i < input.Rows; i++ {
-		copy(encoded[i*params.N:i*params.N+params.L], input.Data[i*params.L:(i+1)*params.L])
-		copy(encoded[i*params.N+params.L:(i+1)*params.N], rmvp.LinearCodeEncoder.EncodeDual(input.Data[i*params.L:(i+1)*params.L]))
+	if code, ok := rmvp.LinearCodeEncoder.(*linearcode.StreamingRandomCode); ok {
+		dual := code.EncodeDualRows(input.Data, input.Rows)
+		for i := uint32(0); i < input.Rows; i++ {
+			copy(encoded[i*params.N:i*params.N+params.L], input.Data[i*params.L:(i+1)*params.L])
+			copy(encoded[i*params.N+params.L:(i+1)*params.N], dual[i*params.K:(i+1)*params.K])
+		}
+	} else {
+		for i := uint32(0); i < input.Rows; i++ {
+			copy(encoded[i*params.N:i*params.N+params.L], input.Data[i*params.L:(i+1)*params.L])
+			copy(encoded[i*params.N+params.L:(i+1)*params.N], rmvp.LinearCodeEncoder.EncodeDual(input.Data[i*params.L:(i+1)*params.L]))
+		}
 	}
 
 	params.Field.AddVectors(encoded, 0, encoded, 0, mask, 0, uint64(len(encoded)))
@@ -41,9 +56,12 @@ func (rmvp *RingSlsnMVP) Encode(sk SecretKey, input dataobjects.Matrix, mask []u
 	TransformToBlockwise(encoded, blockwizeEncodedMatrix, params.M, params.N, params.S)
 
 	return &dataobjects.Matrix{
-		Rows: params.M,
-		Cols: params.N,
-		Data: blockwizeEncodedMatrix,
+		Rows:    params.M,
+		Cols:    params.N,
+		Data:    blockwizeEncodedMatrix,
+		Layout:  dataobjects.BlockRowMajor,
+		Blocks:  params.S,
+		Modulus: params.P,
 	}
 }
 
@@ -90,3 +108,17 @@ func (rmvp *RingSlsnMVP) Answer(encodedMatrix dataobjects.Matrix, clientQuery Sl
 func (rmvp *RingSlsnMVP) Decode(sk SecretKey, response []uint32, aux SlsnAux) []uint32 {
 	return rmvp.SlsnMVP.Decode(sk, response, aux)
 }
+
+func (rmvp *RingSlsnMVP) QueryBatch(sk SecretKey, vecs [][]uint32) (*SlsnBatchQuery, []SlsnAux) {
+	return batchQueries(rmvp.SlsnMVP.Params, vecs, func(vec []uint32) (*SlsnQuery, *SlsnAux) {
+		return rmvp.Query(sk, vec)
+	})
+}
+
+func (rmvp *RingSlsnMVP) AnswerBatch(encodedMatrix dataobjects.Matrix, clientQuery SlsnBatchQuery) []uint32 {
+	return rmvp.SlsnMVP.AnswerBatch(encodedMatrix, clientQuery)
+}
+
+func (rmvp *RingSlsnMVP) DecodeBatch(sk SecretKey, response []uint32, auxes []SlsnAux) [][]uint32 {
+	return rmvp.SlsnMVP.DecodeBatch(sk, response, auxes)
+}
@@ -0,0 +1,343 @@
+package mvp
+
+import (
+	"RandomLinearCodePIR/dataobjects"
+	"RandomLinearCodePIR/ecc"
+	"RandomLinearCodePIR/linearcode"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Scheme is the common interface of the MVP schemes, so that callers can pick one by name through NewScheme.
+// Masks, Query, Aux and Response are opaque, a scheme returns an error when given the values of another scheme.
+type Scheme interface {
+	KeyGen(seed int64) SecretKey
+	GenerateTDM(sk SecretKey) Masks
+	Encode(sk SecretKey, input dataobjects.Matrix, masks Masks) (*dataobjects.Matrix, error)
+	Query(sk SecretKey, vec []uint32) (Query, Aux)
+	Answer(encodedMatrix *dataobjects.Matrix, query Query) (Response, error)
+	Decode(sk SecretKey, response Response, aux Aux) ([]uint32, error)
+	SaveEncoded(path string, encoded *dataobjects.Matrix) error
+	LoadEncoded(path string) (*dataobjects.Matrix, error)
+	MapEncoded(path string) (*dataobjects.MappedMatrix, error)
+}
+
+type (
+	Masks    = any
+	Query    = any
+	Aux      = any
+	Response = any
+)
+
+// SchemeConfig holds the params of all schemes, each scheme reads the ones it uses.
+// N = K + L, Field defaults to the prime field of P. SlsnMVP and RingSlsnMVP also run over a RingZ2k or
+// BinaryField Field, P is then Field.Mod(), LpnMVP needs a prime field.
+type SchemeConfig struct {
+	Name  string
+	Field dataobjects.Field
+	P     uint32
+	M     uint32
+	L     uint32
+	K     uint32
+	// Split-LSN: number of blocks S, with S | N, and the linearcode code name
+	S          uint32
+	LinearCode string
+	// LPN
+	Epsi      float64
+	M_1       uint32
+	ECCLength uint32
+	ECCName   string
+	ECCPoints []uint32
+	// Goroutines Answer runs on, see SlsnParams.Workers and LpnParams.Workers
+	Workers uint32
+}
+
+func (config SchemeConfig) field() dataobjects.Field {
+	if config.Field != nil {
+		return config.Field
+	}
+	return dataobjects.NewPrimeField(config.P)
+}
+
+func (config SchemeConfig) slsnParams() (SlsnParams, error) {
+	n := config.K + config.L
+	field, p := config.field(), config.P
+	if !supportedField(field) {
+		return SlsnParams{}, fmt.Errorf("unsupported field %T", field)
+	}
+	if !nativeKernels(field) {
+		p = field.Mod()
+	}
+	if (nativeKernels(field) && p < 2) || config.M == 0 || config.K == 0 || config.L == 0 {
+		return SlsnParams{}, errors.New("P, M, K and L must be positive")
+	}
+	if config.S == 0 || n%config.S != 0 {
+		return SlsnParams{}, fmt.Errorf("S = %d must divide N = %d", config.S, n)
+	}
+	switch config.LinearCode {
+	case "", linearcode.Random, linearcode.StreamingRandom:
+	case linearcode.QuasiCyclic, linearcode.Vandermonde:
+		if !nativeKernels(field) {
+			return SlsnParams{}, fmt.Errorf("linear code %q needs a prime field, not %T", config.LinearCode, field)
+		}
+	default:
+		return SlsnParams{}, fmt.Errorf("unsupported linear code %q", config.LinearCode)
+	}
+	return SlsnParams{
+		Field:      field,
+		S:          config.S,
+		K:          config.K,
+		N:          n,
+		M:          config.M,
+		L:          config.L,
+		B:          n / config.S,
+		P:          p,
+		LinearCode: config.LinearCode,
+		Workers:    config.Workers,
+	}, nil
+}
+
+func (config SchemeConfig) lpnParams() (LpnParams, error) {
+	if config.P < 2 || config.M == 0 || config.K == 0 || config.L == 0 {
+		return LpnParams{}, errors.New("P, M, K and L must be positive")
+	}
+	if !nativeKernels(config.field()) {
+		return LpnParams{}, fmt.Errorf("%s needs a prime field, not %T", LpnScheme, config.field())
+	}
+	if config.M_1 == 0 || config.M%config.M_1 != 0 {
+		return LpnParams{}, fmt.Errorf("M_1 = %d must divide M = %d", config.M_1, config.M)
+	}
+	if config.ECCName == ecc.ExtensionReedSolomon {
+		return LpnParams{}, fmt.Errorf("%s needs a code over F_P, %s is not supported", LpnScheme, config.ECCName)
+	}
+	params := LpnParams{
+		Field:     config.field(),
+		Epsi:      config.Epsi,
+		N:         config.K + config.L,
+		M:         config.M,
+		L:         config.L,
+		K:         config.K,
+		M_1:       config.M_1,
+		P:         config.P,
+		ECCLength: config.ECCLength,
+		ECCName:   config.ECCName,
+		ECCPoints: config.ECCPoints,
+		Workers:   config.Workers,
+	}
+	if _, err := ecc.NewECCCode(params.eccConfig()); err != nil {
+		return LpnParams{}, err
+	}
+	return params, nil
+}
+
+// SchemeConstructor builds a scheme from its config, returning an error if the config does not describe one
+type SchemeConstructor func(config SchemeConfig) (Scheme, error)
+
+var (
+	schemeLock sync.RWMutex
+	schemes    = map[string]SchemeConstructor{}
+)
+
+// RegisterScheme makes a scheme available to NewScheme under name. Registering a name twice panics.
+func RegisterScheme(name string, constructor SchemeConstructor) {
+	schemeLock.Lock()
+	defer schemeLock.Unlock()
+	if _, ok := schemes[name]; ok {
+		panic("MVP scheme registered twice: " + name)
+	}
+	schemes[name] = constructor
+}
+
+// RegisteredSchemes returns the names of all registered schemes in sorted order
+func RegisteredSchemes() []string {
+	schemeLock.RLock()
+	defer schemeLock.RUnlock()
+	names := make([]string, 0, len(schemes))
+	for name := range schemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func NewScheme(config SchemeConfig) (Scheme, error) {
+	schemeLock.RLock()
+	constructor, ok := schemes[config.Name]
+	schemeLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported MVP scheme %q", config.Name)
+	}
+	return constructor(config)
+}
+
+func init() {
+	RegisterScheme(SlsnScheme, func(c SchemeConfig) (Scheme, error) {
+		params, err := c.slsnParams()
+		if err != nil {
+			return nil, err
+		}
+		return (&SlsnMVP{Params: params}).Scheme(), nil
+	})
+	RegisterScheme(RingSlsnScheme, func(c SchemeConfig) (Scheme, error) {
+		params, err := c.slsnParams()
+		if err != nil {
+			return nil, err
+		}
+		if params.LinearCode != "" {
+			return nil, fmt.Errorf("%s picks its own linear code, LinearCode %q is not supported", RingSlsnScheme, params.LinearCode)
+		}
+		if p := params.Field.GetChar(); nativeKernels(params.Field) && (params.K >= p || params.L >= p) {
+			return nil, fmt.Errorf("%s needs K and L below %d", RingSlsnScheme, p)
+		}
+		return (&RingSlsnMVP{SlsnMVP: SlsnMVP{Params: params}}).Scheme(), nil
+	})
+	RegisterScheme(LpnScheme, func(c SchemeConfig) (Scheme, error) {
+		params, err := c.lpnParams()
+		if err != nil {
+			return nil, err
+		}
+		return (&LpnMVP{Params: params}).Scheme(), nil
+	})
+}
+
+// Scheme returns slsn as a Scheme
+func (slsn *SlsnMVP) Scheme() Scheme {
+	return slsnScheme{slsn}
+}
+
+// Scheme returns rmvp as a Scheme
+func (rmvp *RingSlsnMVP) Scheme() Scheme {
+	return ringSlsnScheme{rmvp}
+}
+
+// Scheme returns lpn as a Scheme
+func (lpn *LpnMVP) Scheme() Scheme {
+	return lpnScheme{lpn}
+}
+
+// wrongType is the error of an adapter given a value of another scheme
+func wrongType(scheme, what string, value any) error {
+	return fmt.Errorf("%s: %s of type %T belongs to another scheme", scheme, what, value)
+}
+
+type slsnScheme struct {
+	*SlsnMVP
+}
+
+func (s slsnScheme) GenerateTDM(sk SecretKey) Masks {
+	return s.SlsnMVP.GenerateTDM(sk)
+}
+
+func (s slsnScheme) Encode(sk SecretKey, input dataobjects.Matrix, masks Masks) (*dataobjects.Matrix, error) {
+	mask, ok := masks.([]uint32)
+	if !ok {
+		return nil, wrongType(SlsnScheme, "masks", masks)
+	}
+	return s.SlsnMVP.Encode(sk, input, mask), nil
+}
+
+func (s slsnScheme) Query(sk SecretKey, vec []uint32) (Query, Aux) {
+	return s.SlsnMVP.Query(sk, vec)
+}
+
+func (s slsnScheme) Answer(encodedMatrix *dataobjects.Matrix, query Query) (Response, error) {
+	clientQuery, ok := query.(*SlsnQuery)
+	if !ok {
+		return nil, wrongType(SlsnScheme, "query", query)
+	}
+	return s.SlsnMVP.Answer(*encodedMatrix, *clientQuery), nil
+}
+
+func (s slsnScheme) Decode(sk SecretKey, response Response, aux Aux) ([]uint32, error) {
+	answer, ok := response.([]uint32)
+	if !ok {
+		return nil, wrongType(SlsnScheme, "response", response)
+	}
+	slsnAux, ok := aux.(*SlsnAux)
+	if !ok {
+		return nil, wrongType(SlsnScheme, "aux", aux)
+	}
+	return s.SlsnMVP.Decode(sk, answer, *slsnAux), nil
+}
+
+type ringSlsnScheme struct {
+	*RingSlsnMVP
+}
+
+func (s ringSlsnScheme) GenerateTDM(sk SecretKey) Masks {
+	return s.RingSlsnMVP.GenerateTDM(sk)
+}
+
+func (s ringSlsnScheme) Encode(sk SecretKey, input dataobjects.Matrix, masks Masks) (*dataobjects.Matrix, error) {
+	mask, ok := masks.([]uint32)
+	if !ok {
+		return nil, wrongType(RingSlsnScheme, "masks", masks)
+	}
+	return s.RingSlsnMVP.Encode(sk, input, mask), nil
+}
+
+func (s ringSlsnScheme) Query(sk SecretKey, vec []uint32) (Query, Aux) {
+	return s.RingSlsnMVP.Query(sk, vec)
+}
+
+func (s ringSlsnScheme) Answer(encodedMatrix *dataobjects.Matrix, query Query) (Response, error) {
+	clientQuery, ok := query.(*SlsnQuery)
+	if !ok {
+		return nil, wrongType(RingSlsnScheme, "query", query)
+	}
+	return s.RingSlsnMVP.Answer(*encodedMatrix, *clientQuery), nil
+}
+
+func (s ringSlsnScheme) Decode(sk SecretKey, response Response, aux Aux) ([]uint32, error) {
+	answer, ok := response.([]uint32)
+	if !ok {
+		return nil, wrongType(RingSlsnScheme, "response", response)
+	}
+	slsnAux, ok := aux.(*SlsnAux)
+	if !ok {
+		return nil, wrongType(RingSlsnScheme, "aux", aux)
+	}
+	return s.RingSlsnMVP.Decode(sk, answer, *slsnAux), nil
+}
+
+type lpnScheme struct {
+	*LpnMVP
+}
+
+func (s lpnScheme) GenerateTDM(sk SecretKey) Masks {
+	return s.LpnMVP.GenerateTDM(sk)
+}
+
+func (s lpnScheme) Encode(sk SecretKey, input dataobjects.Matrix, masks Masks) (*dataobjects.Matrix, error) {
+	mask, ok := masks.([][]uint32)
+	if !ok {
+		return nil, wrongType(LpnScheme, "masks", masks)
+	}
+	return s.LpnMVP.Encode(sk, input, mask), nil
+}
+
+func (s lpnScheme) Query(sk SecretKey, vec []uint32) (Query, Aux) {
+	return s.LpnMVP.Query(sk, vec)
+}
+
+func (s lpnScheme) Answer(encodedMatrix *dataobjects.Matrix, query Query) (Response, error) {
+	clientQuery, ok := query.(*LpnQuery)
+	if !ok {
+		return nil, wrongType(LpnScheme, "query", query)
+	}
+	return s.LpnMVP.Answer(encodedMatrix, clientQuery), nil
+}
+
+func (s lpnScheme) Decode(sk SecretKey, response Response, aux Aux) ([]uint32, error) {
+	lpnResponse, ok := response.(*LpnResponse)
+	if !ok {
+		return nil, wrongType(LpnScheme, "response", response)
+	}
+	lpnAux, ok := aux.(*LpnAux)
+	if !ok {
+		return nil, wrongType(LpnScheme, "aux", aux)
+	}
+	return s.LpnMVP.Decode(sk, lpnResponse, lpnAux), nil
+}
@@ -16,6 +16,9 @@ type SecretKey struct {
 	TDMKey          int64
 	PreLoadedMatrix []uint32
 	TDM             *tdm.TDM
+	// Code keyed by LinearCodeKey, rebuilt from the key if nil. The random code is not kept, its P is the
+	// PreLoadedMatrix already.
+	LinearCode linearcode.LinearCode
 }
 
 // N = K + L denotes the length of the codeword
@@ -25,8 +28,9 @@ type SecretKey struct {
 // B denotes the block size
 // We assume N = S x B
 type SlsnParams struct {
+	// A PrimeField of P runs on the native kernels, a RingZ2k or BinaryField on the field kernels
 	Field dataobjects.Field
-	// Temporarily add P here
+	// Temporarily add P here, Field.Mod() for the fields other than prime fields
 	P uint32
 	S uint32
 	B uint32
@@ -34,6 +38,38 @@ type SlsnParams struct {
 	L uint32
 	N uint32
 	M uint32
+	// Name of the linearcode code the database is encoded with, linearcode.Random if empty
+	LinearCode string
+	// Goroutines Answer splits the rows over, serial if at most 1. The answers do not depend on it.
+	Workers uint32
+}
+
+// linearCode returns the code named by LinearCode, a random code is sampled from seed
+func (params SlsnParams) linearCode(seed int64) linearcode.LinearCode {
+	name := params.LinearCode
+	if name == "" {
+		name = linearcode.Random
+	}
+	if name == linearcode.ExtensionVandermonde {
+		panic("SlsnMVP needs a code over Field, " + name + " is not supported")
+	}
+	if !nativeKernels(params.Field) && name != linearcode.Random && name != linearcode.StreamingRandom {
+		panic("SlsnMVP over a field other than a prime field only supports the random codes, not " + name)
+	}
+	return linearcode.GetLinearCode(linearcode.LinearCodeConfig{
+		Name:  name,
+		K:     params.K,
+		L:     params.L,
+		Field: params.Field,
+		Seed:  seed,
+	})
+}
+
+func (slsn *SlsnMVP) linearCode(sk SecretKey) linearcode.LinearCode {
+	if sk.LinearCode != nil {
+		return sk.LinearCode
+	}
+	return slsn.Params.linearCode(sk.LinearCodeKey)
 }
 
 type SlsnQuery struct {
@@ -46,16 +82,32 @@ type SlsnAux struct {
 	Dur   time.Duration
 }
 
+// SlsnBatchQuery holds Count query vectors of length N back to back
+type SlsnBatchQuery struct {
+	Vecs  []uint32
+	Count uint32
+}
+
 func (slsn *SlsnMVP) KeyGen(seed int64) SecretKey {
 	params := slsn.Params
+	code := params.linearCode(seed)
+	// The key holds P once: the random code as the dual matrix of the native product, the other codes
+	// as themselves since they encode without a dense matrix
+	var preLoaded []uint32
+	if _, ok := code.(*linearcode.RandomCode); ok {
+		preLoaded = code.Generate1DDualMatrix(params.L, params.K, params.Field, seed)
+		code = nil
+	}
 	return SecretKey{
 		LinearCodeKey:   seed,
-		PreLoadedMatrix: linearcode.Generate1DDualMatrix(params.L, params.K, params.Field, seed),
+		LinearCode:      code,
+		PreLoadedMatrix: preLoaded,
 		TDM: &tdm.TDM{
 			M: params.M,
 			N: params.N,
-			// NOTE: Now TDM only support Q = 2^x + 1, Change this to Field later
+			// NOTE: Now the NTT of TDM only support Q = 2^x + 1, other fields multiply the circulants directly
 			Q:      params.P,
+			Field:  params.Field,
 			SeedL:  seed + 1,
 			SeedPL: seed + 1<<10,
 			SeedC:  seed + 1<<11,
@@ -71,14 +123,29 @@ func (slsn *SlsnMVP) GenerateTDM(sk SecretKey) []uint32 {
 
 func (slsn *SlsnMVP) Encode(sk SecretKey, input dataobjects.Matrix, mask []uint32) *dataobjects.Matrix {
 	params := slsn.Params
-	rlcMatrix := linearcode.Generate1DRLCMatrix(params.L, params.K, params.Field, sk.LinearCodeKey)
+	code := slsn.linearCode(sk)
 	encoded := dataobjects.AlignedMake[uint32](uint64(input.Rows * params.N))
 
-	for i := uint32(0); i < input.Rows; i++ {
-		copy(encoded[i*params.N:i*params.N+params.L], input.Data[i*params.L:(i+1)*params.L])
+	switch code := code.(type) {
+	case *linearcode.StreamingRandomCode:
+		dual := code.EncodeDualRows(input.Data, input.Rows)
+		for i := uint32(0); i < input.Rows; i++ {
+			copy(encoded[i*params.N:i*params.N+params.L], input.Data[i*params.L:(i+1)*params.L])
+			copy(encoded[i*params.N+params.L:(i+1)*params.N], dual[i*params.K:(i+1)*params.K])
+		}
+	case *linearcode.RandomCode:
+		rlcMatrix := code.Generate1DRLCMatrix(params.L, params.K, params.Field, sk.LinearCodeKey)
+		for i := uint32(0); i < input.Rows; i++ {
+			copy(encoded[i*params.N:i*params.N+params.L], input.Data[i*params.L:(i+1)*params.L])
 
-		MatVecProduct(rlcMatrix, input.Data[i*input.Cols:(i+1)*input.Cols], encoded[i*params.N+params.L:(i+1)*params.N],
-			params.K, params.L, params.P)
+			params.matVecProduct(rlcMatrix, input.Data[i*input.Cols:(i+1)*input.Cols], encoded[i*params.N+params.L:(i+1)*params.N],
+				params.K, params.L)
+		}
+	default:
+		for i := uint32(0); i < input.Rows; i++ {
+			copy(encoded[i*params.N:i*params.N+params.L], input.Data[i*params.L:(i+1)*params.L])
+			copy(encoded[i*params.N+params.L:(i+1)*params.N], code.EncodeDual(input.Data[i*input.Cols:(i+1)*input.Cols]))
+		}
 	}
 
 	// Add Masks
@@ -88,26 +155,28 @@ func (slsn *SlsnMVP) Encode(sk SecretKey, input dataobjects.Matrix, mask []uint3
 	TransformToBlockwise(encoded, blockwizeEncodedMatrix, params.M, params.N, params.S)
 
 	return &dataobjects.Matrix{
-		Rows: params.M,
-		Cols: params.N,
-		Data: blockwizeEncodedMatrix,
+		Rows:    params.M,
+		Cols:    params.N,
+		Data:    blockwizeEncodedMatrix,
+		Layout:  dataobjects.BlockRowMajor,
+		Blocks:  params.S,
+		Modulus: params.P,
 	}
 }
 
 func (slsn *SlsnMVP) Query(sk SecretKey, vec []uint32) (*SlsnQuery, *SlsnAux) {
 	params := slsn.Params
 
-	PofDual := sk.PreLoadedMatrix
-	if len(PofDual) == 0 {
-		PofDual = linearcode.Generate1DDualMatrix(params.L, params.K, params.Field, sk.LinearCodeKey)
-	}
-
 	// Sample codeword c From NullSpace
 	nullspaceCoeff := params.Field.SampleVector(params.K)
 
 	queryVector := dataobjects.AlignedMake[uint32](uint64(params.N))
 
-	MatVecProduct(PofDual, nullspaceCoeff, queryVector, params.L, params.K, params.P)
+	if len(sk.PreLoadedMatrix) > 0 {
+		params.matVecProduct(sk.PreLoadedMatrix, nullspaceCoeff, queryVector, params.L, params.K)
+	} else {
+		copy(queryVector, slsn.linearCode(sk).EncodeLSN(nullspaceCoeff))
+	}
 
 	copy(queryVector[params.L:params.N], nullspaceCoeff[:params.K])
 
@@ -138,9 +207,22 @@ func (slsn *SlsnMVP) Query(sk SecretKey, vec []uint32) (*SlsnQuery, *SlsnAux) {
 
 func (slsn *SlsnMVP) Answer(encodedMatrix dataobjects.Matrix, clientQuery SlsnQuery) []uint32 {
 	params := slsn.Params
+	encodedMatrix.AssertLayout(params.encodedLayout())
 	result := dataobjects.AlignedMake[uint32](uint64(params.S * params.M))
 
-	BlockMatVecProduct(encodedMatrix.Data, clientQuery.Vec, result, params.M, params.N, params.S, params.P)
+	if params.Workers <= 1 {
+		params.blockMatVecProduct(encodedMatrix.Data, clientQuery.Vec, result, params.M, params.N, params.S)
+		return result
+	}
+
+	// Rows [start, end) of each M x B block, the same products BlockMatVecProduct computes
+	parallelRows(params.Workers, params.M, func(start, end uint32) {
+		for blk := uint64(0); blk < uint64(params.S); blk++ {
+			offset := blk*uint64(params.M)*uint64(params.B) + uint64(start)*uint64(params.B)
+			params.matVecProduct(encodedMatrix.Data[offset:], clientQuery.Vec[blk*uint64(params.B):], result[blk*uint64(params.M)+uint64(start):],
+				end-start, params.B)
+		}
+	})
 	return result
 }
 
@@ -151,7 +233,7 @@ func (slsn *SlsnMVP) Decode(sk SecretKey, response []uint32, aux SlsnAux) []uint
 
 	result := dataobjects.AlignedMake[uint32](uint64(params.M))
 
-	BlockVecMatProduct(response, vec, result, params.S, params.M, 1, params.P)
+	params.blockVecMatProduct(response, vec, result, params.S, params.M, 1)
 	// Unmask
 	for i := uint32(0); i < params.M; i++ {
 		result[i] = params.Field.Sub(result[i], aux.Masks[i])
@@ -159,3 +241,77 @@ func (slsn *SlsnMVP) Decode(sk SecretKey, response []uint32, aux SlsnAux) []uint
 
 	return result
 }
+
+// QueryBatch builds one query per vector, answered together by AnswerBatch
+func (slsn *SlsnMVP) QueryBatch(sk SecretKey, vecs [][]uint32) (*SlsnBatchQuery, []SlsnAux) {
+	return batchQueries(slsn.Params, vecs, func(vec []uint32) (*SlsnQuery, *SlsnAux) {
+		return slsn.Query(sk, vec)
+	})
+}
+
+func batchQueries(params SlsnParams, vecs [][]uint32, query func(vec []uint32) (*SlsnQuery, *SlsnAux)) (*SlsnBatchQuery, []SlsnAux) {
+	count := uint32(len(vecs))
+	batch := dataobjects.AlignedMake[uint32](uint64(count * params.N))
+	auxes := make([]SlsnAux, count)
+	for i, vec := range vecs {
+		q, aux := query(vec)
+		copy(batch[uint32(i)*params.N:(uint32(i)+1)*params.N], q.Vec)
+		auxes[i] = *aux
+	}
+	return &SlsnBatchQuery{Vecs: batch, Count: count}, auxes
+}
+
+// AnswerBatch answers all queries of the batch in one pass over the encoded matrix, response i is
+// the Answer to query i and starts at i*S*M. Like Answer it splits the rows over Params.Workers.
+func (slsn *SlsnMVP) AnswerBatch(encodedMatrix dataobjects.Matrix, clientQuery SlsnBatchQuery) []uint32 {
+	params := slsn.Params
+	encodedMatrix.AssertLayout(params.encodedLayout())
+	count := clientQuery.Count
+	result := dataobjects.AlignedMake[uint32](uint64(count * params.S * params.M))
+	if count == 0 {
+		return result
+	}
+
+	if params.Workers <= 1 {
+		params.blockMatMatProduct(encodedMatrix.Data, clientQuery.Vecs, result, params.M, params.N, params.S, count)
+		return result
+	}
+
+	// Block blk of every query back to back, so that a worker runs the kernel on its rows of one block
+	b := uint64(params.B)
+	vecBlocks := make([][]uint32, params.S)
+	for blk := range vecBlocks {
+		vecBlocks[blk] = dataobjects.AlignedMake[uint32](uint64(count) * b)
+		for v := uint64(0); v < uint64(count); v++ {
+			src := v*uint64(params.N) + uint64(blk)*b
+			copy(vecBlocks[blk][v*b:(v+1)*b], clientQuery.Vecs[src:src+b])
+		}
+	}
+
+	size := uint64(params.S) * uint64(params.M)
+	parallelRows(params.Workers, params.M, func(start, end uint32) {
+		rows := uint64(end - start)
+		out := dataobjects.AlignedMake[uint32](uint64(count) * rows)
+		for blk := uint64(0); blk < uint64(params.S); blk++ {
+			offset := blk*uint64(params.M)*b + uint64(start)*b
+			params.blockMatMatProduct(encodedMatrix.Data[offset:], vecBlocks[blk], out, end-start, params.B, 1, count)
+			for v := uint64(0); v < uint64(count); v++ {
+				dst := v*size + blk*uint64(params.M) + uint64(start)
+				copy(result[dst:dst+rows], out[v*rows:(v+1)*rows])
+			}
+		}
+	})
+	return result
+}
+
+// DecodeBatch decodes the responses of AnswerBatch with the auxes of QueryBatch
+func (slsn *SlsnMVP) DecodeBatch(sk SecretKey, response []uint32, auxes []SlsnAux) [][]uint32 {
+	params := slsn.Params
+	size := params.S * params.M
+
+	results := make([][]uint32, len(auxes))
+	for i := range auxes {
+		results[i] = slsn.Decode(sk, response[uint32(i)*size:(uint32(i)+1)*size], auxes[i])
+	}
+	return results
+}
@@ -1,6 +1,7 @@
 package pir
 
 import (
+	"RandomLinearCodePIR/dataobjects"
 	"RandomLinearCodePIR/utils"
 	"math/rand"
 )
@@ -67,6 +68,7 @@ func (p *BasePIR) Encode(sk SecretKey, matrix Matrix) *Matrix {
 		Cols:      p.Params.PackedSize,
 		EntryBits: 32,
 		Data:      packedData,
+		Layout:    dataobjects.PackedColMajor,
 	}
 }
 
@@ -149,6 +151,8 @@ func makeVectors(queryVector []uint32, flipVector []uint32,
 }
 
 func (p *BasePIR) Answer(matrix *Matrix, clientQuery *BasePIRQuery) *BasePIRAnswer {
+	matrix.AssertLayout(p.encodedLayout())
+
 	rows := matrix.Rows
 	cols := matrix.Cols
 
@@ -194,3 +198,56 @@ func (p *BasePIR) Decode(sk SecretKey, index uint64, response *BasePIRAnswer, au
 
 	return (val >> bitOffset) & 1
 }
+
+// Scheme name recorded in the header of a saved encoded database
+const BaseScheme = "BasePIR"
+
+// PackedSize is derived from Rows, so it is not recorded
+func (p *BasePIR) fileParams() []dataobjects.Param {
+	return []dataobjects.Param{
+		dataobjects.UintParam("Rows", p.Params.Rows),
+		dataobjects.UintParam("Cols", p.Params.Cols),
+		dataobjects.UintParam("NumberOfBlocks", p.Params.NumberOfBlocks),
+		dataobjects.UintParam("CodewordLength", p.Params.CodewordLength),
+	}
+}
+
+func (p *BasePIR) encodedLayout() (dataobjects.Layout, uint32, uint32, uint32) {
+	return dataobjects.PackedColMajor, p.Params.CodewordLength, p.Params.PackedSize, 0
+}
+
+// SaveEncoded writes the output of Encode to path
+func (p *BasePIR) SaveEncoded(path string, encoded *Matrix) error {
+	if err := encoded.CheckLayout(p.encodedLayout()); err != nil {
+		return err
+	}
+	return dataobjects.SaveMatrix(path, BaseScheme, p.fileParams(), encoded)
+}
+
+// LoadEncoded reads a database saved by SaveEncoded with the same params and sets PackedSize as Encode does
+func (p *BasePIR) LoadEncoded(path string) (*Matrix, error) {
+	m, err := dataobjects.LoadMatrix(path, BaseScheme, p.fileParams())
+	if err != nil {
+		return nil, err
+	}
+	p.Params.PackedSize = (p.Params.Rows + 31) / 32
+	if err := m.CheckLayout(p.encodedLayout()); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MapEncoded maps a database saved by SaveEncoded instead of reading it, pass its Matrix to Answer and
+// Close it when done
+func (p *BasePIR) MapEncoded(path string) (*dataobjects.MappedMatrix, error) {
+	mm, err := dataobjects.MapMatrix(path, BaseScheme, p.fileParams())
+	if err != nil {
+		return nil, err
+	}
+	p.Params.PackedSize = (p.Params.Rows + 31) / 32
+	if err := mm.CheckLayout(p.encodedLayout()); err != nil {
+		mm.Close()
+		return nil, err
+	}
+	return mm, nil
+}
@@ -1,3 +1,5 @@
+//go:build cgo && !purego
+
 // matrix_vector_multiply.c
 #include <stdint.h>
 #include <stdlib.h>
@@ -1,3 +1,5 @@
+//go:build cgo && !purego
+
 package pir
 
 /*
@@ -8,24 +10,6 @@ import (
 	"unsafe"
 )
 
-func VecMatMulF4(bit1Result, bitPResult, bit1Matrix, bitPMatrix, bit1Vec, bitPVec []uint32, rows, cols uint32) {
-	// (a + bp) * (x + yp)
-	ax := make([]uint32, cols)
-	by := make([]uint32, cols)
-	ay := make([]uint32, cols)
-	bx := make([]uint32, cols)
-
-	VecMatrixMulF2(ax, bit1Matrix, bit1Vec, rows, cols)
-	VecMatrixMulF2(by, bitPMatrix, bitPVec, rows, cols)
-	VecMatrixMulF2(ay, bitPMatrix, bit1Vec, rows, cols)
-	VecMatrixMulF2(bx, bit1Matrix, bitPVec, rows, cols)
-
-	for i := range ax {
-		bit1Result[i] = ax[i] ^ by[i]
-		bitPResult[i] = ay[i] ^ bx[i] ^ by[i]
-	}
-}
-
 func VecMatrixMulF2(result, matrix, vector []uint32, rows, cols uint32) {
 	cVector := (*C.uint32_t)(unsafe.Pointer(&vector[0]))
 	cMatrix := (*C.uint32_t)(unsafe.Pointer(&matrix[0]))
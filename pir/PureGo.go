@@ -0,0 +1,60 @@
+//go:build purego || !cgo
+
+package pir
+
+// Go implementations of the kernels in BitMVP.c, used when building with the purego tag or
+// CGO_ENABLED=0.
+
+// Vector^T * Matrix over F2 while matrix is flattened by rows
+func VecMatrixMulF2(result, matrix, vector []uint32, rows, cols uint32) {
+	for i := uint32(0); i < rows; i++ {
+		if vector[i] == 1 {
+			row := matrix[i*cols : (i+1)*cols]
+			for j := range row {
+				result[j] ^= row[j]
+			}
+		}
+	}
+}
+
+// For 2D Split LSN, vectors are packed by 32 entries
+func MatrixColXORByBlock2D(vector_1, vector_2, matrixData, result_1, result_2 []uint32, rows, cols, block_size uint32) {
+	nblocks := (rows + block_size - 1) / block_size
+
+	for k := uint32(0); k < nblocks; k++ {
+		blockStart := k * block_size
+		blockEnd := min(blockStart+block_size, rows)
+
+		R1 := result_1[uint64(k)*uint64(cols):]
+		R2 := result_2[uint64(k)*uint64(cols):]
+
+		for row := blockStart; row < blockEnd; row++ {
+			rowp := matrixData[uint64(row)*uint64(cols) : uint64(row+1)*uint64(cols)]
+			if (vector_1[row>>5]>>(row&31))&1 == 1 {
+				for j := range rowp {
+					R1[j] ^= rowp[j]
+				}
+			}
+			if (vector_2[row>>5]>>(row&31))&1 == 1 {
+				for j := range rowp {
+					R2[j] ^= rowp[j]
+				}
+			}
+		}
+	}
+}
+
+// For 1D Split LSN
+func MatrixColXORByBlock(vector, matrixData, result []uint32, rows, cols, block_size uint32) {
+	for k := uint32(0); k < rows/block_size; k++ {
+		res := result[k*cols : (k+1)*cols]
+		for i := k * block_size; i < (k+1)*block_size; i++ {
+			if vector[i] == 1 {
+				row := matrixData[i*cols : (i+1)*cols]
+				for j := range row {
+					res[j] ^= row[j]
+				}
+			}
+		}
+	}
+}
@@ -6,12 +6,8 @@ import (
 	"math/rand"
 )
 
-type Matrix struct {
-	Rows      uint32
-	Cols      uint32
-	EntryBits uint32
-	Data      []uint32
-}
+// Matrix is the shared matrix type, the encoded database of BasePIR is dataobjects.PackedColMajor
+type Matrix = dataobjects.Matrix
 
 type MatrixF4 struct {
 	Rows      uint32
@@ -107,6 +103,7 @@ func GeneratePrimeFieldMatrix(rows, cols, p uint32, seed int64) Matrix {
 		Cols:      cols,
 		EntryBits: uint32(math.Ceil(math.Log2(float64(p)))),
 		Data:      data,
+		Modulus:   p,
 	}
 }
 
@@ -127,3 +124,21 @@ func PackAndTransposeMatrix(matrix [][]uint32, rows, cols uint32) []uint32 {
 
 	return packedData
 }
+
+func VecMatMulF4(bit1Result, bitPResult, bit1Matrix, bitPMatrix, bit1Vec, bitPVec []uint32, rows, cols uint32) {
+	// (a + bp) * (x + yp)
+	ax := make([]uint32, cols)
+	by := make([]uint32, cols)
+	ay := make([]uint32, cols)
+	bx := make([]uint32, cols)
+
+	VecMatrixMulF2(ax, bit1Matrix, bit1Vec, rows, cols)
+	VecMatrixMulF2(by, bitPMatrix, bitPVec, rows, cols)
+	VecMatrixMulF2(ay, bitPMatrix, bit1Vec, rows, cols)
+	VecMatrixMulF2(bx, bit1Matrix, bitPVec, rows, cols)
+
+	for i := range ax {
+		bit1Result[i] = ax[i] ^ by[i]
+		bitPResult[i] = ay[i] ^ bx[i] ^ by[i]
+	}
+}
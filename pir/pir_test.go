@@ -1,6 +1,7 @@
 package pir
 
 import (
+	"RandomLinearCodePIR/dataobjects"
 	"RandomLinearCodePIR/utils"
 	"fmt"
 	"math/rand"
@@ -63,6 +64,47 @@ func TestBasePIR(t *testing.T) {
 	}
 }
 
+// Test that a saved BasePIR database is answered correctly by a server that loads or maps it
+func TestBasePIRSaveLoad(t *testing.T) {
+	row, col := uint32(1<<8), uint32(1<<6)
+	params := BaseParams{Rows: row, Cols: col, NumberOfBlocks: 16, CodewordLength: col + 32}
+	client := &BasePIR{Params: params}
+
+	matrix := GenerateMatrix(row, col, 1, 1)
+	sk := client.KeyGen(1, 2, 32, 1)
+	path := t.TempDir() + "/base.db"
+	if err := client.SaveEncoded(path, client.Encode(sk, matrix)); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	server := &BasePIR{Params: params}
+	encoded, err := server.LoadEncoded(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	for _, queryIndex := range []uint64{0, 77, uint64(row*col) - 1} {
+		query, aux := client.Query(sk, queryIndex)
+		if val := client.Decode(sk, queryIndex, server.Answer(encoded, query), aux); val != matrix.Data[queryIndex] {
+			t.Fatalf("index %d: got %d, expected %d", queryIndex, val, matrix.Data[queryIndex])
+		}
+	}
+
+	mapped, err := (&BasePIR{Params: params}).MapEncoded(path)
+	if err != nil {
+		t.Fatalf("map: %v", err)
+	}
+	defer mapped.Close()
+	query, aux := client.Query(sk, 5)
+	if val := client.Decode(sk, 5, server.Answer(&mapped.Matrix, query), aux); val != matrix.Data[5] {
+		t.Fatalf("answer over the mapped file: got %d, expected %d", val, matrix.Data[5])
+	}
+
+	params.CodewordLength++
+	if _, err := (&BasePIR{Params: params}).LoadEncoded(path); err == nil {
+		t.Fatalf("database loaded with another codeword length")
+	}
+}
+
 func TestMixedSLSNPIR(t *testing.T) {
 	lambda := uint32(32)
 	row := uint32(1 << 8)
@@ -161,6 +203,7 @@ func BenchmarkAnswer(b *testing.B) {
 	}
 
 	encodedMatrix := GenerateMatrix(pi.Params.CodewordLength, pi.Params.PackedSize, 32, 1)
+	encodedMatrix.Layout = dataobjects.PackedColMajor
 
 	var totalDuration time.Duration
 	b.ResetTimer()
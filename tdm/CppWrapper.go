@@ -1,3 +1,5 @@
+//go:build cgo && !purego
+
 package tdm
 
 /*
@@ -31,3 +33,29 @@ func NTT(coeff []uint32, n, root, q uint32) {
 		C.size_t(n), C.uint32_t(root), C.uint32_t(q))
 	runtime.KeepAlive(coeff)
 }
+
+func NTT_Convolution64(dataA, dataB, result []uint64, degree uint32, root, q uint64) {
+	C.ntt_convolution64((*C.uint64_t)(unsafe.Pointer(&dataA[0])),
+		(*C.uint64_t)(unsafe.Pointer(&dataB[0])),
+		(*C.uint64_t)(unsafe.Pointer(&result[0])),
+		C.size_t(degree), C.uint64_t(root), C.uint64_t(q))
+	runtime.KeepAlive(dataA)
+	runtime.KeepAlive(dataB)
+	runtime.KeepAlive(result)
+}
+
+func NthRootOfUnity64(q, n uint64) uint64 {
+	return uint64(C.NthRootOfUnity64(C.uint64_t(q), C.uint64_t(n)))
+}
+
+func NTT64(coeff []uint64, n uint32, root, q uint64) {
+	C.ntt64((*C.uint64_t)(unsafe.Pointer(&coeff[0])),
+		C.size_t(n), C.uint64_t(root), C.uint64_t(q))
+	runtime.KeepAlive(coeff)
+}
+
+func INTT64(coeff []uint64, n uint32, root, q uint64) {
+	C.intt64((*C.uint64_t)(unsafe.Pointer(&coeff[0])),
+		C.size_t(n), C.uint64_t(root), C.uint64_t(q))
+	runtime.KeepAlive(coeff)
+}
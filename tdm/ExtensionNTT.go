@@ -0,0 +1,59 @@
+package tdm
+
+import (
+	"RandomLinearCodePIR/dataobjects"
+)
+
+// ExtensionNTT performs the in-place forward NTT over GF(p^d) on n elements stored as element-major
+// limbs, i.e. a[k] = sum_j a[j] * root^(jk) with natural ordering as in NTT. root must be a primitive
+// n-th root of unity of the field, see ExtensionField.NthRootOfUnity.
+func ExtensionNTT(field *dataobjects.ExtensionField, a []uint32, n uint32, root []uint32) {
+	d := field.Degree()
+	elem := func(i uint32) []uint32 { return a[i*d : (i+1)*d] }
+
+	// Bit-reversal permutation
+	j := uint32(0)
+	for i := uint32(1); i < n; i++ {
+		bit := n >> 1
+		for j&bit != 0 {
+			j ^= bit
+			bit >>= 1
+		}
+		j ^= bit
+		if i < j {
+			for t := uint32(0); t < d; t++ {
+				a[i*d+t], a[j*d+t] = a[j*d+t], a[i*d+t]
+			}
+		}
+	}
+
+	wlen := field.NewElement()
+	w := field.NewElement()
+	u := field.NewElement()
+	v := field.NewElement()
+	for length := uint32(2); length <= n; length <<= 1 {
+		field.ExpElement(wlen, root, uint64(n/length))
+
+		for i := uint32(0); i < n; i += length {
+			copy(w, field.Embed(1))
+			for k := uint32(0); k < length/2; k++ {
+				copy(u, elem(i+k))
+				field.MulElement(v, elem(i+k+length/2), w)
+				field.AddElement(elem(i+k), u, v)
+				field.SubElement(elem(i+k+length/2), u, v)
+				field.MulElement(w, w, wlen)
+			}
+		}
+	}
+}
+
+// ExtensionINTT is the inverse of ExtensionNTT for the same root
+func ExtensionINTT(field *dataobjects.ExtensionField, a []uint32, n uint32, root []uint32) {
+	invRoot := field.NewElement()
+	field.InvElement(invRoot, root)
+
+	ExtensionNTT(field, a, n, invRoot)
+
+	invN := field.Base().Inv(n % field.GetChar())
+	field.MulLimbVectorByBase(a, 0, a, 0, invN, uint64(n))
+}
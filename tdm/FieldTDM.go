@@ -0,0 +1,79 @@
+package tdm
+
+import (
+	"RandomLinearCodePIR/dataobjects"
+	"math/rand"
+)
+
+// The trapdoored matrix over a Field without NTT, RingZ2k and BinaryField: the same product
+// S_L * Pi_L * S * Pi_R * S_R with circulants drawn from the field and multiplied directly, which
+// takes time quadratic in the block size for a vector and cubic for a block of the matrix.
+
+// fieldPath reports whether td computes over Field instead of the prime field of Q
+func (td *TDM) fieldPath() bool {
+	if td.Field == nil {
+		return false
+	}
+	_, prime := td.Field.(*dataobjects.PrimeField)
+	return !prime
+}
+
+// fieldCirculantVectorMul returns C * v for the seeded circulant C[t][j] = r[(j - t) mod blockSize]
+func (td *TDM) fieldCirculantVectorMul(blockSize uint32, seed int64, v []uint32) []uint32 {
+	rng := rand.New(rand.NewSource(seed))
+	r := dataobjects.AlignedMake[uint32](uint64(blockSize))
+	for t := range r {
+		r[t] = td.Field.SampleElementWithSeed(rng)
+	}
+
+	result := dataobjects.AlignedMake[uint32](uint64(blockSize))
+	for t := uint32(0); t < blockSize; t++ {
+		acc := uint32(0)
+		for j := uint32(0); j < blockSize; j++ {
+			acc = td.Field.Add(acc, td.Field.Mul(r[(j+blockSize-t)%blockSize], v[j]))
+		}
+		result[t] = acc
+	}
+	return result
+}
+
+// fieldBasicMul returns the basic trapdoored matrix of the seeds times v of length block
+func (td *TDM) fieldBasicMul(v []uint32, seedL, seedPL, seedC, seedPR, seedR int64) []uint32 {
+	// S_R = [I // C] x v
+	resR := dataobjects.AlignedMake[uint32](uint64(ExpansionFactor * td.block))
+	copy(resR, v)
+	copy(resR[td.block:], td.fieldCirculantVectorMul(td.block, seedR, v))
+
+	PermuteVectorInPlace(resR, GetPermutation(ExpansionFactor*td.block, seedPR))
+	resC := td.fieldCirculantVectorMul(ExpansionFactor*td.block, seedC, resR)
+	PermuteVectorInPlace(resC, GetPermutation(ExpansionFactor*td.block, seedPL))
+
+	// S_L = [I | C] x resC
+	vec := td.fieldCirculantVectorMul(td.block, seedL, resC[td.block:])
+	td.Field.AddVectors(resC, 0, resC, 0, vec, 0, uint64(td.block))
+	return resC[:td.block]
+}
+
+func (td *TDM) fieldEvaluationCircuitBasic(v []uint32, addOnSeed int64) []uint32 {
+	return td.fieldBasicMul(v, td.SeedL+addOnSeed, td.SeedPL+addOnSeed, td.SeedC+addOnSeed, td.SeedPR+addOnSeed, td.SeedR+addOnSeed)
+}
+
+// generateFieldTrapDooredMatrix builds the basic trapdoored matrix column by column from unit vectors,
+// so it agrees with fieldEvaluationCircuitBasic by construction
+func (td *TDM) generateFieldTrapDooredMatrix(seedL, seedPL, seedC, seedPR, seedR int64) [][]uint32 {
+	result := make([][]uint32, td.block)
+	for i := range result {
+		result[i] = dataobjects.AlignedMake[uint32](uint64(td.block))
+	}
+
+	unit := dataobjects.AlignedMake[uint32](uint64(td.block))
+	for j := uint32(0); j < td.block; j++ {
+		unit[j] = 1
+		column := td.fieldBasicMul(unit, seedL, seedPL, seedC, seedPR, seedR)
+		unit[j] = 0
+		for i := range result {
+			result[i][j] = column[i]
+		}
+	}
+	return result
+}
@@ -1,9 +1,12 @@
+//go:build cgo && !purego
+
 #include "NTT.h"
 #include <iostream>
 #include <cstdint>
-#include <cstdlib>  // for rand()
+#include <cstdlib>
 #include <cassert>
 #include <string.h>
+#include <vector>
 
 #include "../dataobjects/dataobj.h"
 #include "../dataobjects/fields.h"
@@ -134,13 +137,14 @@ uint32_t NthRootOfUnity(uint32_t M, uint32_t N) {
     assert(_mod_op(M - 1, N) == 0);  // Ensure N divides M-1
     uint32_t phi = M - 1;
 
-    while (true) {
-        uint32_t alpha = 1 + _mod_op((u32)rand(), M - 1); // pick alpha ∈ [1, M-1]
+    // Candidates are tried in order so that every build (including purego) picks the same root
+    for (uint32_t alpha = 2; alpha < M; ++alpha) {
         uint32_t beta = modExponent(alpha, phi / N, M);
-        if (!existSmallN(beta, M, N)) {
+        if ((N == 1 || beta != 1) && !existSmallN(beta, M, N)) {
             return beta;
         }
     }
+    return 1;  // only reached for M = 2
 }
 
 inline void print_array(const char* name, const u32* arr, size_t n) {
@@ -409,4 +413,122 @@ inline void poly_mod_xt_minus_1(const u32* a, u32* b, size_t n, size_t t, u32 mo
         if (j == t) j = 0;
         b[j] = _mod_op(b[j] + a[i], mod);
     }
-}
\ No newline at end of file
+}
+// 64-BIT NTT
+
+typedef unsigned __int128 u128;
+
+inline u64 mul_mod64(u64 a, u64 b, u64 mod) {
+    return (u64)(((u128)a * b) % mod);
+}
+
+inline u64 mod_pow64(u64 base, u64 exp, u64 mod) {
+    u64 result = 1 % mod;
+    base %= mod;
+    while (exp > 0) {
+        if (exp & 1)
+            result = mul_mod64(result, base, mod);
+        base = mul_mod64(base, base, mod);
+        exp >>= 1;
+    }
+    return result;
+}
+
+void ntt64(u64* a, size_t n, u64 root, u64 mod) {
+    // Bit-reversal permutation
+    size_t j = 0;
+    for (size_t i = 1; i < n; ++i) {
+        size_t bit = n >> 1;
+        while (j & bit) {
+            j ^= bit;
+            bit >>= 1;
+        }
+        j ^= bit;
+        if (i < j) {
+            std::swap(a[i], a[j]);
+        }
+    }
+
+    for (size_t len = 2; len <= n; len <<= 1) {
+        u64 wlen = mod_pow64(root, n / len, mod);
+
+        for (size_t i = 0; i < n; i += len) {
+            u64 w = 1;
+            for (size_t j = 0; j < len / 2; ++j) {
+                u64 u = a[i + j];
+                u64 v = mul_mod64(a[i + j + len / 2], w, mod);
+                u64 sum = u + v;
+                a[i + j] = (sum < u || sum >= mod) ? sum - mod : sum;
+                a[i + j + len / 2] = (u >= v) ? u - v : mod - (v - u);
+                w = mul_mod64(w, wlen, mod);
+            }
+        }
+    }
+}
+
+void intt64(u64* a, size_t n, u64 root, u64 mod) {
+    u64 inv_root = mod_pow64(root, mod - 2, mod);
+
+    ntt64(a, n, inv_root, mod);
+
+    u64 inv_n = mod_pow64(n % mod, mod - 2, mod);
+    if (USE_FAST_CODE) {
+        Field64MulVector(a, 0, a, 0, inv_n, n, mod);
+    } else {
+        for (size_t i = 0; i < n; ++i) {
+            a[i] = mul_mod64(a[i], inv_n, mod);
+        }
+    }
+}
+
+// Cyclic convolution of length n using the 64-bit NTT
+void ntt_convolution64(const u64* a, const u64* b, u64* result, size_t n, u64 root, u64 mod) {
+    std::vector<u64> fa(a, a + n);
+    std::vector<u64> fb(b, b + n);
+
+    ntt64(fa.data(), n, root, mod);
+    ntt64(fb.data(), n, root, mod);
+
+    if (USE_FAST_CODE) {
+        Field64MulVectors(result, 0, fa.data(), 0, fb.data(), 0, n, mod);
+    } else {
+        for (size_t i = 0; i < n; ++i) {
+            result[i] = mul_mod64(fa[i], fb[i], mod);
+        }
+    }
+
+    intt64(result, n, root, mod);
+}
+
+// Return a primitive N-th root of unity modulo the prime M. Unlike NthRootOfUnity, candidates are
+// tried deterministically and primitivity is checked against the prime factors of N only.
+uint64_t NthRootOfUnity64(uint64_t M, uint64_t N) {
+    assert(M > 1);
+    assert(N > 0 && (M - 1) % N == 0);  // Ensure N divides M-1
+
+    std::vector<u64> factors;
+    u64 rest = N;
+    for (u64 q = 2; q * q <= rest; ++q) {
+        if (rest % q == 0) {
+            factors.push_back(q);
+            while (rest % q == 0) rest /= q;
+        }
+    }
+    if (rest > 1) factors.push_back(rest);
+
+    for (u64 alpha = 2; alpha < M; ++alpha) {
+        u64 beta = mod_pow64(alpha, (M - 1) / N, M);
+        bool primitive = true;
+        for (u64 q : factors) {
+            if (mod_pow64(beta, N / q, M) == 1) {
+                primitive = false;
+                break;
+            }
+        }
+        if (primitive) {
+            return beta;
+        }
+    }
+
+    return 1;  // only reached for M = 2
+}
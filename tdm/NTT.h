@@ -21,6 +21,15 @@ void ntt_convolution(const u32* a, const u32* b, u32* result, size_t n, u32 root
 
 uint32_t NthRootOfUnity(u32 M, u32 N);
 
+// 64-bit variants for moduli up to 2^64, e.g. 60-bit NTT-friendly primes
+void ntt64(u64* a, size_t n, u64 root, u64 mod);
+
+void intt64(u64* a, size_t n, u64 root, u64 mod);
+
+void ntt_convolution64(const u64* a, const u64* b, u64* result, size_t n, u64 root, u64 mod);
+
+uint64_t NthRootOfUnity64(u64 M, u64 N);
+
 //FIXME u32 mod_pow(u32 base, u32 exp, u32 mod);
 //FIXME u32 mod_inv(u32 a, u32 mod);
 
@@ -0,0 +1,205 @@
+//go:build purego || !cgo
+
+package tdm
+
+import (
+	"RandomLinearCodePIR/dataobjects"
+	"math/bits"
+)
+
+// Go implementations of the kernels in NTT.cpp, used when building with the purego tag or
+// CGO_ENABLED=0. Roots of unity are searched in the same order as the C code.
+
+func NTT_Convolution(dataA, dataB, result []uint32, degree, root, q uint32) {
+	fa := dataobjects.AlignedMake[uint32](uint64(degree))
+	fb := dataobjects.AlignedMake[uint32](uint64(degree))
+	copy(fa, dataA[:degree])
+	copy(fb, dataB[:degree])
+
+	NTT(fa, degree, root, q)
+	NTT(fb, degree, root, q)
+
+	for i := uint32(0); i < degree; i++ {
+		result[i] = uint32(uint64(fa[i]) * uint64(fb[i]) % uint64(q))
+	}
+
+	NTT(result, degree, modPow(root, q-2, q), q)
+	dataobjects.FieldMulVector(result, 0, result, 0, modPow(degree%q, q-2, q), uint64(degree), q)
+}
+
+// Return a primitive n-th root of unity modulo q, n must divide q-1
+func NthRootOfUnity(q, n uint32) uint32 {
+	if q <= 1 || (q-1)%n != 0 {
+		panic("n must divide q-1")
+	}
+
+	for alpha := uint32(2); alpha < q; alpha++ {
+		beta := modPow(alpha, (q-1)/n, q)
+		if (n == 1 || beta != 1) && !existSmallN(beta, q, n) {
+			return beta
+		}
+	}
+	return 1
+}
+
+// In-place forward NTT with natural ordering, a[k] = sum_j a[j] * root^(jk)
+func NTT(coeff []uint32, n, root, q uint32) {
+	a := coeff[:n]
+	bitReverse(len(a), func(i, j int) { a[i], a[j] = a[j], a[i] })
+
+	Q := uint64(q)
+	for length := uint32(2); length <= n; length <<= 1 {
+		wlen := uint64(modPow(root, n/length, q))
+
+		for i := uint32(0); i < n; i += length {
+			w := uint64(1)
+			for j := i; j < i+length/2; j++ {
+				u := uint64(a[j])
+				v := uint64(a[j+length/2]) * w % Q
+				a[j] = uint32((u + v) % Q)
+				a[j+length/2] = uint32((Q + u - v) % Q)
+				w = w * wlen % Q
+			}
+		}
+	}
+}
+
+func NTT_Convolution64(dataA, dataB, result []uint64, degree uint32, root, q uint64) {
+	fa := dataobjects.AlignedMake[uint64](uint64(degree))
+	fb := dataobjects.AlignedMake[uint64](uint64(degree))
+	copy(fa, dataA[:degree])
+	copy(fb, dataB[:degree])
+
+	NTT64(fa, degree, root, q)
+	NTT64(fb, degree, root, q)
+
+	dataobjects.Field64MulVectors(result, 0, fa, 0, fb, 0, uint64(degree), q)
+
+	INTT64(result, degree, root, q)
+}
+
+// Return a primitive n-th root of unity modulo the prime q, checked against the prime factors of n
+func NthRootOfUnity64(q, n uint64) uint64 {
+	if q <= 1 || n == 0 || (q-1)%n != 0 {
+		panic("n must divide q-1")
+	}
+
+	var factors []uint64
+	rest := n
+	for f := uint64(2); f*f <= rest; f++ {
+		if rest%f == 0 {
+			factors = append(factors, f)
+			for rest%f == 0 {
+				rest /= f
+			}
+		}
+	}
+	if rest > 1 {
+		factors = append(factors, rest)
+	}
+
+	for alpha := uint64(2); alpha < q; alpha++ {
+		beta := modPow64(alpha, (q-1)/n, q)
+		primitive := true
+		for _, f := range factors {
+			if modPow64(beta, n/f, q) == 1 {
+				primitive = false
+				break
+			}
+		}
+		if primitive {
+			return beta
+		}
+	}
+	return 1
+}
+
+func NTT64(coeff []uint64, n uint32, root, q uint64) {
+	a := coeff[:n]
+	bitReverse(len(a), func(i, j int) { a[i], a[j] = a[j], a[i] })
+
+	for length := uint32(2); length <= n; length <<= 1 {
+		wlen := modPow64(root, uint64(n/length), q)
+
+		for i := uint32(0); i < n; i += length {
+			w := uint64(1)
+			for j := i; j < i+length/2; j++ {
+				u := a[j]
+				v := mulMod64(a[j+length/2], w, q)
+				sum, carry := bits.Add64(u, v, 0)
+				if carry != 0 || sum >= q {
+					sum -= q
+				}
+				a[j] = sum
+				if u >= v {
+					a[j+length/2] = u - v
+				} else {
+					a[j+length/2] = q - (v - u)
+				}
+				w = mulMod64(w, wlen, q)
+			}
+		}
+	}
+}
+
+func INTT64(coeff []uint64, n uint32, root, q uint64) {
+	NTT64(coeff, n, modPow64(root, q-2, q), q)
+	dataobjects.Field64MulVector(coeff, 0, coeff, 0, modPow64(uint64(n)%q, q-2, q), uint64(n), q)
+}
+
+func bitReverse(n int, swap func(i, j int)) {
+	j := 0
+	for i := 1; i < n; i++ {
+		bit := n >> 1
+		for j&bit != 0 {
+			j ^= bit
+			bit >>= 1
+		}
+		j ^= bit
+		if i < j {
+			swap(i, j)
+		}
+	}
+}
+
+// Check if beta^k == 1 mod q for some 2 <= k < n
+func existSmallN(beta, q, n uint32) bool {
+	b := uint64(beta)
+	for k := uint32(2); k < n; k++ {
+		b = b * uint64(beta) % uint64(q)
+		if b == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func modPow(base, exp, q uint32) uint32 {
+	result := uint64(1)
+	b := uint64(base) % uint64(q)
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			result = result * b % uint64(q)
+		}
+		b = b * b % uint64(q)
+	}
+	return uint32(result)
+}
+
+func mulMod64(a, b, q uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	_, rem := bits.Div64(hi%q, lo, q)
+	return rem
+}
+
+func modPow64(base, exp, q uint64) uint64 {
+	result := 1 % q
+	base %= q
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			result = mulMod64(result, base, q)
+		}
+		base = mulMod64(base, base, q)
+	}
+	return result
+}
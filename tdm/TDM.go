@@ -1,11 +1,5 @@
 package tdm
 
-/*
-#cgo CFLAGS: -I../TDM
-#cgo LDFLAGS: -L../TDM -L/opt/homebrew/lib -lNTT -lntl -lgmp -lstdc++
-#include "NTT.h"
-*/
-import "C"
 import (
 	"RandomLinearCodePIR/dataobjects"
 	"RandomLinearCodePIR/utils"
@@ -14,15 +8,21 @@ import (
 )
 
 const (
-	USE_FAST_CODE_FOR_CIRCULANT = true
-	ExpansionFactor             = 2
-	SliceSeedShift              = 13758
+	ExpansionFactor = 2
+	SliceSeedShift  = 13758
 )
 
+// USE_FAST_CODE_FOR_CIRCULANT draws circulant polynomials from the AES generator instead of math/rand,
+// it only takes effect together with dataobjects.USE_FAST_CODE
+var USE_FAST_CODE_FOR_CIRCULANT = true
+
 type TDM struct {
-	M      uint32
-	N      uint32
-	Q      uint32
+	M uint32
+	N uint32
+	Q uint32
+	// Field of the matrix, the prime field of Q if nil. Over the other fields there is no NTT and the
+	// circulants are multiplied directly, see fieldPath.
+	Field  dataobjects.Field
 	SeedL  int64
 	SeedC  int64
 	SeedR  int64
@@ -59,6 +59,9 @@ func (td *TDM) GenerateTrapDooredMatrix(seedL, seedPL, seedC, seedPR, seedR int6
 
 // The basic Trapdoor matrix has the form R = S_L * Pi_L * S * Pi_R * S_R where it expands k x k matrix by factor of the ExpansionFactor (2)
 func (td *TDM) GenerateBasicTrapDooredMatrix(seedL, seedPL, seedC, seedPR, seedR int64) [][]uint32 {
+	if td.fieldPath() {
+		return td.generateFieldTrapDooredMatrix(seedL, seedPL, seedC, seedPR, seedR)
+	}
 	S_R := GetQuasiCyclicMatrix(td.block, td.Q, seedR)
 
 	permR := GetPermutation(ExpansionFactor*td.block, seedPR)
@@ -135,7 +138,9 @@ func (td *TDM) EvaluationCircuitPerSlice(v []uint32, sliceNum int64) []uint32 {
 		for i := uint32(0); i < td.m/td.block; i++ {
 			// Calculate the seed for each block, and use ECBasic to evaluate
 			temp := td.EvaluationCircuitBasic(bv, int64(i*td.m/td.block+j)+sliceNum*SliceSeedShift)
-			if dataobjects.USE_FAST_CODE {
+			if td.fieldPath() {
+				td.Field.AddVectors(masks, uint64(i*td.block), masks, uint64(i*td.block), temp, 0, uint64(td.block))
+			} else if dataobjects.USE_FAST_CODE {
 				dataobjects.FieldAddVectors(masks, uint64(i*td.block), masks, uint64(i*td.block), temp, 0, uint64(td.block), td.Q)
 			} else {
 				for k := uint32(0); k < td.block; k++ {
@@ -149,6 +154,9 @@ func (td *TDM) EvaluationCircuitPerSlice(v []uint32, sliceNum int64) []uint32 {
 }
 
 func (td *TDM) EvaluationCircuitBasic(v []uint32, addOnSeed int64) []uint32 {
+	if td.fieldPath() {
+		return td.fieldEvaluationCircuitBasic(v, addOnSeed)
+	}
 	// S_R = [I | C] x v
 	resR := dataobjects.AlignedMake[uint32](uint64(ExpansionFactor * td.block))
 	copy(resR, v)
@@ -354,12 +362,18 @@ func GetCirculantMatrix(k, q uint32, seed int64) [][]uint32 {
 }
 
 func (td *TDM) updateInternalUseParams() {
-	td.block = td.determineBlockSize(td.M, td.N)
+	if td.fieldPath() {
+		td.block = roundUpToPowerOf2(min(td.M, td.N))
+	} else {
+		td.block = td.determineBlockSize(td.M, td.N)
+	}
 	td.m = utils.RoundUp(td.M, td.block)
 	td.n = utils.RoundUp(td.N, td.block)
 
-	td.rootK = NthRootOfUnity(td.Q, td.block)
-	td.root2K = NthRootOfUnity(td.Q, td.block*2)
+	if !td.fieldPath() {
+		td.rootK = NthRootOfUnity(td.Q, td.block)
+		td.root2K = NthRootOfUnity(td.Q, td.block*2)
+	}
 }
 
 func roundUpToPowerOf2(m uint32) uint32 {
@@ -0,0 +1,106 @@
+//go:build purego || !cgo
+
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	crand "crypto/rand"
+	"encoding/binary"
+	"sync"
+)
+
+// Go implementation of the AES-CTR generator in aes_rnd.h and rnd_api.cpp, used when building with
+// the purego tag or CGO_ENABLED=0. A seed s keys AES-128 with the 16 bytes of (s, s) in little
+// endian, and block i of the stream is the encryption of the 128-bit little endian counter i+1,
+// so seeded vectors are identical to the cgo build. The C generator is thread local, here a single
+// generator is shared under a lock.
+
+type aesRandom struct {
+	mu     sync.Mutex
+	block  cipher.Block
+	ctrLo  uint64
+	ctrHi  uint64
+	seeded bool
+}
+
+var aesrnd aesRandom
+
+func (r *aesRandom) reseed(seed []byte) {
+	block, err := aes.NewCipher(seed)
+	if err != nil {
+		panic(err)
+	}
+	r.block = block
+	r.ctrLo, r.ctrHi = 0, 0
+	r.seeded = true
+}
+
+func (r *aesRandom) randomBytes(out []byte) {
+	if !r.seeded {
+		var seed [16]byte
+		if _, err := crand.Read(seed[:]); err != nil {
+			panic(err)
+		}
+		r.reseed(seed[:])
+	}
+
+	r.ctrLo++
+	if r.ctrLo == 0 {
+		r.ctrHi++
+	}
+	var ctr [16]byte
+	binary.LittleEndian.PutUint64(ctr[:8], r.ctrLo)
+	binary.LittleEndian.PutUint64(ctr[8:], r.ctrHi)
+	r.block.Encrypt(out, ctr[:])
+}
+
+// Fills data[:length] with the stream as little endian words, the last block may be partially used
+func (r *aesRandom) randomizeVector(data []uint32, length uint32) {
+	var buf [16]byte
+	for i := uint32(0); i < length; i += 4 {
+		r.randomBytes(buf[:])
+		for j := uint32(0); j < 4 && i+j < length; j++ {
+			data[i+j] = binary.LittleEndian.Uint32(buf[4*j:])
+		}
+	}
+}
+
+func randomize_vector(data []uint32, length uint32) {
+	if data == nil || length == 0 {
+		return
+	}
+
+	aesrnd.mu.Lock()
+	defer aesrnd.mu.Unlock()
+	aesrnd.randomizeVector(data, length)
+}
+
+func randomize_vector_with_seed(data []uint32, length uint32, seed int64) {
+	var seed16 [16]byte
+	binary.LittleEndian.PutUint64(seed16[:8], uint64(seed))
+	binary.LittleEndian.PutUint64(seed16[8:], uint64(seed))
+
+	aesrnd.mu.Lock()
+	defer aesrnd.mu.Unlock()
+	aesrnd.reseed(seed16[:])
+	if data != nil && length > 0 {
+		aesrnd.randomizeVector(data, length)
+	}
+}
+
+func randomize_vector_with_modulus(data []uint32, length uint32, modulus uint32) {
+	randomize_vector(data, length)
+	vector_mod_op(data, length, modulus)
+}
+
+func randomize_vector_with_modulus_and_seed(data []uint32, length uint32, modulus uint32, seed int64) {
+	randomize_vector_with_seed(data, length, seed)
+	vector_mod_op(data, length, modulus)
+}
+
+func vector_mod_op(data []uint32, length uint32, modulus uint32) {
+	for i := uint32(0); i < length; i++ {
+		data[i] %= modulus
+	}
+}
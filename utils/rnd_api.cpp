@@ -1,3 +1,5 @@
+//go:build cgo && !purego
+
 #include "rnd_api.h"
 #include "aes_rnd.h"
 #include "../dataobjects/mod_simd.h"
@@ -200,9 +200,10 @@ func GeneratePrimeFieldMatrix(rows, cols, p uint32, seed int64) dataobjects.Matr
 	}
 
 	return dataobjects.Matrix{
-		Rows: rows,
-		Cols: cols,
-		Data: data,
+		Rows:    rows,
+		Cols:    cols,
+		Data:    data,
+		Modulus: p,
 	}
 }
 